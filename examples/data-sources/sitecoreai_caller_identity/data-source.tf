@@ -0,0 +1,16 @@
+# Use the caller identity data source to check who the provider is authenticated as
+data "sitecoreai_caller_identity" "current" {}
+
+variable "expected_organization_id" {
+  type = string
+}
+
+# Fail the plan if running against the wrong organization
+resource "terraform_data" "organization_guard" {
+  lifecycle {
+    precondition {
+      condition     = data.sitecoreai_caller_identity.current.organization_id == var.expected_organization_id
+      error_message = "Authenticated to organization ${data.sitecoreai_caller_identity.current.organization_id} using ${data.sitecoreai_caller_identity.current.credential_source} credentials, expected ${var.expected_organization_id}."
+    }
+  }
+}
@@ -0,0 +1,11 @@
+# List the deleted environments of a project that can still be restored
+data "sitecoreai_deleted_environments" "example" {
+  project_id = "your-project-id"
+}
+
+output "deleted_environments" {
+  value = {
+    for environment in data.sitecoreai_deleted_environments.example.environments :
+    environment.name => environment.deleted_at
+  }
+}
@@ -0,0 +1,19 @@
+# List the regions available to the organization
+data "sitecoreai_regions" "available" {}
+
+# Host the project in a specific region, failing the plan if it is not available
+variable "region" {
+  type = string
+}
+
+resource "sitecoreai_project" "example" {
+  name   = "example-project"
+  region = var.region
+
+  lifecycle {
+    precondition {
+      condition     = contains(data.sitecoreai_regions.available.names, var.region)
+      error_message = "Region ${var.region} is not available, choose one of ${join(", ", data.sitecoreai_regions.available.names)}."
+    }
+  }
+}
@@ -0,0 +1,17 @@
+# List the Sitecore versions environments can run
+data "sitecoreai_sitecore_versions" "supported" {}
+
+# Keep the environment on the default version, it is upgraded when the default changes
+resource "sitecoreai_environment" "example" {
+  name             = "development"
+  project_id       = var.project_id
+  sitecore_version = data.sitecoreai_sitecore_versions.supported.default_version
+}
+
+variable "project_id" {
+  type = string
+}
+
+output "supported_versions" {
+  value = data.sitecoreai_sitecore_versions.supported.versions
+}
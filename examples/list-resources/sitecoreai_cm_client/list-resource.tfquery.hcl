@@ -0,0 +1,7 @@
+list "sitecoreai_cm_client" "example" {
+  provider = sitecoreai
+
+  config {
+    environment_id = "environment-12345"
+  }
+}
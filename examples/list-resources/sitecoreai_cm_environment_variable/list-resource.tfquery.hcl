@@ -0,0 +1,7 @@
+list "sitecoreai_cm_environment_variable" "example" {
+  provider = sitecoreai
+
+  config {
+    environment_id = "environment-12345"
+  }
+}
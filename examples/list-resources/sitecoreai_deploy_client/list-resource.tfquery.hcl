@@ -0,0 +1,3 @@
+list "sitecoreai_deploy_client" "all" {
+  provider = sitecoreai
+}
@@ -0,0 +1,7 @@
+list "sitecoreai_editing_host_build_client" "example" {
+  provider = sitecoreai
+
+  config {
+    environment_id = "environment-12345"
+  }
+}
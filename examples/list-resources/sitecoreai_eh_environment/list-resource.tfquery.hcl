@@ -0,0 +1,7 @@
+list "sitecoreai_eh_environment" "example" {
+  provider = sitecoreai
+
+  config {
+    project_id = "project-12345"
+  }
+}
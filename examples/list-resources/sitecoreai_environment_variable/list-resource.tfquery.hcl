@@ -0,0 +1,8 @@
+list "sitecoreai_environment_variable" "example" {
+  provider = sitecoreai
+
+  config {
+    environment_id = "environment-12345"
+    target         = "CM"
+  }
+}
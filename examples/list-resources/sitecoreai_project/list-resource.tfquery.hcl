@@ -0,0 +1,3 @@
+list "sitecoreai_project" "all" {
+  provider = sitecoreai
+}
@@ -14,4 +14,7 @@ provider "sitecoreai" {
   # export SITECOREAI_CLIENT_SECRET="your-client-secret"
   # or alternative,
   # export SITECOREAI_USE_CLI=1
+  #
+  # To reuse tokens across runs, eg. in CI with many workspaces
+  # export SITECOREAI_TOKEN_CACHE=1
 }
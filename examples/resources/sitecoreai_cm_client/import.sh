@@ -0,0 +1,5 @@
+# Import using the ID of the client
+terraform import sitecoreai_cm_client.example "client-12345"
+
+# Or using the OAuth client ID, the client secret cannot be imported
+terraform import sitecoreai_cm_client.example "AbCdEf0123456789"
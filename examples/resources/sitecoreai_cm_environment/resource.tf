@@ -8,6 +8,24 @@ resource "sitecoreai_cm_environment" "cm_production" {
   name       = "production"
   project_id = data.sitecoreai_project.default.id
   is_prod    = true
+
+  # High availability can only be enabled on production environments
+  high_availability_enabled = true
+
+  # Bring the environment back if it is deleted in the portal, instead of creating an empty one
+  restore_if_deleted = true
+
+  # Provisioning can take longer than the provider default of 30 minutes in busy regions
+  timeouts {
+    create = "60m"
+  }
+}
+
+# Keep an environment that was managed with sitecoreai_environment when switching to the
+# split resources, instead of destroying and recreating it (requires Terraform 1.8 or later)
+moved {
+  from = sitecoreai_environment.production
+  to   = sitecoreai_cm_environment.cm_production
 }
 
 # Output CM environment details
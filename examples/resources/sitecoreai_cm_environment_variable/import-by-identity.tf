@@ -0,0 +1,7 @@
+import {
+  to = sitecoreai_cm_environment_variable.example
+  identity = {
+    environment_id = "environment-12345"
+    name           = "SXA_ENVIRONMENT_NAME"
+  }
+}
@@ -1,2 +1,5 @@
 # Import using the format `<environment_id>:target:<variable_name>`
-terraform import sitecore_cm_environment_variable.non_secret "environment-12345:Sitecore_GraphQL_ExposePlayground"
\ No newline at end of file
+terraform import sitecore_cm_environment_variable.non_secret "environment-12345:Sitecore_GraphQL_ExposePlayground"
+
+# Or using the names of the project and environment, in the format `<project>/<environment>/<variable_name>`
+terraform import sitecoreai_cm_environment_variable.non_secret "XMC/production/Sitecore_GraphQL_ExposePlayground"
\ No newline at end of file
@@ -20,3 +20,11 @@ resource "sitecore_cm_environment_variable" "secret" {
   name           = "CM_SECRET_VAR"
   secret_value   = "s3cr3t_p@ssw0rd" # Sensitive value (masked in logs/state)
 }
+
+
+# Keep a variable that was managed with sitecoreai_environment_variable and target = "CM"
+# (requires Terraform 1.8 or later)
+moved {
+  from = sitecoreai_environment_variable.playground
+  to   = sitecore_cm_environment_variable.non_secret
+}
@@ -25,3 +25,17 @@ output "deploy_client_secret" {
   description = "The name of the Deploy client"
   sensitive   = true
 }
+
+# Keep the client secret out of the state in plain text by encrypting it with a
+# PGP public key or an age recipient. Decrypt it with:
+#   terraform output -raw encrypted_deploy_client_secret | base64 --decode | age --decrypt -i key.txt
+resource "sitecoreai_deploy_client" "encrypted" {
+  name        = "terraform-encrypted-deploy-client"
+  description = "Deploy client with an encrypted secret"
+  pgp_key     = "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"
+}
+
+output "encrypted_deploy_client_secret" {
+  value       = sitecoreai_deploy_client.encrypted.encrypted_client_secret
+  description = "The client secret of the Deploy client, encrypted with the age recipient"
+}
@@ -0,0 +1,6 @@
+import {
+  to = sitecoreai_edge_client.example
+  identity = {
+    client_id = "oauth-client-12345"
+  }
+}
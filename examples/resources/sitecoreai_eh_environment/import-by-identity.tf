@@ -0,0 +1,6 @@
+import {
+  to = sitecoreai_eh_environment.example
+  identity = {
+    environment_id = "environment-12345"
+  }
+}
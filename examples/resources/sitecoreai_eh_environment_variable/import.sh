@@ -1,2 +1,5 @@
 # Import using the format `<environment_id>:target:<variable_name>`
-terraform import sitecoreai_eh_environment_variable.non_secret "environment-12345:EH_NON_SECRET_VAR"
\ No newline at end of file
+terraform import sitecoreai_eh_environment_variable.non_secret "environment-12345:EH_NON_SECRET_VAR"
+
+# Or using the names of the project and environment, in the format `<project>/<environment>/<variable_name>`
+terraform import sitecoreai_eh_environment_variable.non_secret "XMC/production/EH_NON_SECRET_VAR"
\ No newline at end of file
@@ -0,0 +1,6 @@
+import {
+  to = sitecoreai_environment.example
+  identity = {
+    environment_id = "environment-12345"
+  }
+}
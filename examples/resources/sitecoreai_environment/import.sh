@@ -0,0 +1,5 @@
+# Import using the environment ID
+terraform import sitecoreai_environment.example "environment-12345"
+
+# Or using the names of the project and environment, in the format `<project>/<environment>`
+terraform import sitecoreai_environment.example "XMC/production"
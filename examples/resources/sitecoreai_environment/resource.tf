@@ -9,6 +9,13 @@ resource "sitecoreai_environment" "staging" {
   name       = "staging"
   project_id = sitecoreai_project.example.id
   is_prod    = false
+
+  # Deploy the staging branch whenever commits are pushed to it
+  repository_branch = "staging"
+  deploy_on_commit  = true
+
+  # Changing the version upgrades the environment, see the sitecoreai_sitecore_versions data source
+  sitecore_version = "1.5"
 }
 
 # Output the environment details
@@ -0,0 +1,8 @@
+import {
+  to = sitecoreai_environment_variable.example
+  identity = {
+    environment_id = "environment-12345"
+    name           = "SXA_ENVIRONMENT_NAME"
+    target         = "CM"
+  }
+}
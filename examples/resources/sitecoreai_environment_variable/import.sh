@@ -1,2 +1,5 @@
 # Import using the format `<environment_id>:target:<variable_name>`
-terraform import sitecoreai_environment_variable.example "environment-12345:CM:SXA_ENVIRONMENT_NAME"
\ No newline at end of file
+terraform import sitecoreai_environment_variable.example "environment-12345:CM:SXA_ENVIRONMENT_NAME"
+
+# Or using the names of the project and environment, in the format `<project>/<environment>/<variable_name>@<target>`
+terraform import sitecoreai_environment_variable.example "XMC/production/SXA_ENVIRONMENT_NAME@CM"
\ No newline at end of file
@@ -0,0 +1,6 @@
+import {
+  to = sitecoreai_project.example
+  identity = {
+    project_id = "project-12345"
+  }
+}
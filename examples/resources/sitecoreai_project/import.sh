@@ -0,0 +1,5 @@
+# Import using the project ID
+terraform import sitecoreai_project.example "project-12345"
+
+# Or using the name of the project
+terraform import sitecoreai_project.example "XMC"
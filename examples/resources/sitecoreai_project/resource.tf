@@ -2,6 +2,12 @@
 resource "sitecoreai_project" "example" {
   name        = "XMC"
   description = "A project for managing our corporate website"
+
+  # Delete the environments and their clients together with the project
+  force_destroy = true
+
+  # Take over the project when one with the same name already exists
+  adopt_existing = true
 }
 
 # Reference the project ID in outputs
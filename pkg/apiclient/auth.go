@@ -1,10 +1,10 @@
 package apiclient
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
@@ -13,6 +13,9 @@ import (
 	"time"
 )
 
+// defaultAudience is the audience requested for client credentials tokens
+const defaultAudience = "https://api.sitecorecloud.io"
+
 // AuthResponse represents the JWT authentication response
 // from SitecoreAI API
 type AuthResponse struct {
@@ -33,9 +36,22 @@ type CLIUserConfig struct {
 	} `json:"endpoints"`
 }
 
-// authenticate requests a JWT token from SitecoreAI API
+// Authenticate requests a JWT token from SitecoreAI API
 // using client ID and client secret
 func (c *Client) Authenticate() error {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	return c.authenticate()
+}
+
+// authenticate sets the token unless there is one already, the caller must hold tokenMutex
+func (c *Client) authenticate() error {
+
+	// A client without configuration cannot authenticate
+	if c.ConfigError != nil {
+		return c.ConfigError
+	}
 
 	// If we already have a token, no need to authenticate
 	if c.Token != "" {
@@ -44,13 +60,33 @@ func (c *Client) Authenticate() error {
 
 	// If we have token in cli config, no need to authenticate
 	if c.CliConfig != nil && len(c.CliConfig.Endpoints.XMCloud.AccessToken) > 0 {
+		if expiresAt, ok := c.CLITokenExpiry(); ok && time.Now().After(expiresAt) {
+			return fmt.Errorf("the access token in %s expired at %s, run 'dotnet sitecore cloud login' to refresh it", c.CliConfigPath, expiresAt.Format(time.RFC3339))
+		}
 		c.Token = c.CliConfig.Endpoints.XMCloud.AccessToken
 		return nil
 	}
 
+	// The CLI config has no token, and there are no client credentials to fall back to
+	if c.CliConfig != nil && (c.ClientID == "" || c.ClientSecret == "") {
+		return fmt.Errorf("no access token found in %s, run 'dotnet sitecore cloud login' to authenticate the Sitecore CLI", c.CliConfigPath)
+	}
+
+	// Reuse a cached token from a previous run if it has not expired
+	if c.TokenCache != nil {
+		token, err := c.TokenCache.Get(c.AuthURL, defaultAudience, c.ClientID)
+		if err != nil {
+			log.Printf("Ignoring token cache: %v", err)
+		}
+		if token != "" {
+			c.Token = token
+			return nil
+		}
+	}
+
 	// Create request payload
 	payload := url.Values{}
-	payload.Set("audience", "https://api.sitecorecloud.io")
+	payload.Set("audience", defaultAudience)
 	payload.Set("grant_type", "client_credentials")
 	payload.Set("client_id", c.ClientID)
 	payload.Set("client_secret", c.ClientSecret)
@@ -62,7 +98,7 @@ func (c *Client) Authenticate() error {
 		strings.NewReader(payload.Encode()),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -70,7 +106,7 @@ func (c *Client) Authenticate() error {
 	// Send request
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %v", err)
+		return fmt.Errorf("failed to send request: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -78,65 +114,85 @@ func (c *Client) Authenticate() error {
 	// Parse response
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(body))
+		return newAuthError(resp.StatusCode, body, c.CredentialSource)
 	}
 
 	var authResponse AuthResponse
 	err = json.NewDecoder(resp.Body).Decode(&authResponse)
 	if err != nil {
-		return fmt.Errorf("failed to decode response: %v", err)
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	// Set token
 	c.Token = authResponse.AccessToken
 
+	if c.TokenCache != nil {
+		expiresAt := time.Now().Add(time.Duration(authResponse.ExpiresIn) * time.Second)
+		err = c.TokenCache.Put(c.AuthURL, defaultAudience, c.ClientID, c.Token, expiresAt)
+		if err != nil {
+			log.Printf("Unable to write token cache: %v", err)
+		}
+	}
+
 	return nil
 }
 
 // EnsureTokenValid checks if the current token is valid and
 // refreshes it if needed
 func (c *Client) EnsureTokenValid() error {
+	_, err := c.validToken()
+	return err
+}
+
+// validToken returns a valid token, refreshing it if needed. Concurrent callers wait for
+// the first one to authenticate and then share its token, so only one token is requested
+func (c *Client) validToken() (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	// Another caller may have set the token while we waited for the lock
 	if c.Token == "" {
-		return c.Authenticate()
+		err := c.authenticate()
+		return c.Token, err
 	}
 
 	// Parse token to check expiration
-	// This is a simplified check - in production you would properly parse the JWT
-	parts := strings.Split(c.Token, ".")
-	if len(parts) != 3 {
-		return c.Authenticate()
+	claims, err := decodeTokenClaims(c.Token)
+	if err != nil || claims.Expiry == 0 {
+		err := c.authenticate()
+		return c.Token, err
 	}
+	exp := claims.Expiry
 
-	// Decode payload
-	payload, err := base64.RawStdEncoding.DecodeString(parts[1])
-	if err != nil {
-		return c.Authenticate()
+	// Check if token is expired or about to expire (within 5 minutes)
+	if time.Now().Unix() > int64(exp) || time.Now().Unix() > int64(exp)-300 {
+		err := c.authenticate()
+		return c.Token, err
 	}
 
-	var data map[string]interface{}
-	err = json.Unmarshal(payload, &data)
-	if err != nil {
-		return c.Authenticate()
-	}
+	return c.Token, nil
+}
 
-	exp, ok := data["exp"].(float64)
-	if !ok {
-		return c.Authenticate()
+// CLITokenExpiry returns when the access token from the Sitecore CLI config expires.
+// The second return value is false if there is no CLI token or it has no expiry.
+func (c *Client) CLITokenExpiry() (time.Time, bool) {
+	if c.CliConfig == nil || c.CliConfig.Endpoints.XMCloud.AccessToken == "" {
+		return time.Time{}, false
 	}
 
-	// Check if token is expired or about to expire (within 5 minutes)
-	if time.Now().Unix() > int64(exp) || time.Now().Unix() > int64(exp)-300 {
-		return c.Authenticate()
+	claims, err := decodeTokenClaims(c.CliConfig.Endpoints.XMCloud.AccessToken)
+	if err != nil || claims.Expiry == 0 {
+		return time.Time{}, false
 	}
 
-	return nil
+	return time.Unix(int64(claims.Expiry), 0).UTC(), true
 }
 
 func findCLIUserConfig() (*CLIUserConfig, error) {
 
 	configPath, err := findCLIUserConfigPath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get config path: %v", err)
+		return nil, fmt.Errorf("failed to get config path: %w", err)
 	}
 
 	return readCLIUserConfig(configPath)
@@ -149,13 +205,13 @@ func readCLIUserConfig(configPath string) (*CLIUserConfig, error) {
 
 	configData, err := os.ReadFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read user.json: %v", err)
+		return nil, fmt.Errorf("failed to read user.json: %w", err)
 	}
 
 	var config CLIUserConfig
 	err = json.Unmarshal(configData, &config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse user.json: %v", err)
+		return nil, fmt.Errorf("failed to parse user.json: %w", err)
 	}
 
 	return &config, nil
@@ -166,7 +222,7 @@ func findCLIUserConfigPath() (string, error) {
 	// Start from current directory and move up the directory tree
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %v", err)
+		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
 
 	for {
@@ -177,7 +233,7 @@ func findCLIUserConfigPath() (string, error) {
 			// File exists, try to read it
 			_, err := os.ReadFile(configPath)
 			if err != nil {
-				return "", fmt.Errorf("failed to read user.json: %v", err)
+				return "", fmt.Errorf("failed to read user.json: %w", err)
 			}
 
 			return configPath, nil
@@ -0,0 +1,92 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AuthError is returned when the token endpoint rejects the credentials
+type AuthError struct {
+	StatusCode       int
+	Code             string
+	Description      string
+	Body             string
+	CredentialSource string
+}
+
+// oauthErrorResponse represents the structure of OAuth error responses
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// newAuthError parses an error response from the token endpoint
+func newAuthError(statusCode int, body []byte, credentialSource string) *AuthError {
+	authErr := &AuthError{
+		StatusCode:       statusCode,
+		Body:             strings.TrimSpace(string(body)),
+		CredentialSource: credentialSource,
+	}
+
+	var response oauthErrorResponse
+	if err := json.Unmarshal(body, &response); err == nil {
+		authErr.Code = response.Error
+		authErr.Description = response.ErrorDescription
+	}
+
+	return authErr
+}
+
+func (e *AuthError) Error() string {
+	var errorMsg string
+	if e.Code != "" {
+		errorMsg = fmt.Sprintf("authentication failed with status %d: %s", e.StatusCode, e.Code)
+		if e.Description != "" {
+			errorMsg += fmt.Sprintf(" (%s)", e.Description)
+		}
+	} else {
+		errorMsg = fmt.Sprintf("authentication failed with status %d: %s", e.StatusCode, e.Body)
+	}
+
+	if e.CredentialSource != "" {
+		errorMsg += fmt.Sprintf(". Credentials were taken from %s", describeCredentialSource(e.CredentialSource))
+	}
+
+	return errorMsg
+}
+
+// Remediation returns a hint on how to fix common OAuth errors. It is not part of
+// Error, so callers can show it separately from the wrapped error message
+func (e *AuthError) Remediation() string {
+	description := strings.ToLower(e.Description)
+
+	switch {
+	case strings.Contains(description, "audience") || strings.Contains(description, "service not found"):
+		return fmt.Sprintf("The credentials are not allowed to request tokens for the audience %s. Use an automation client created for SitecoreAI rather than a client for another Sitecore product", defaultAudience)
+	case e.Code == "invalid_client" || e.Code == "unauthorized_client":
+		return "The client ID or client secret is wrong, or the automation client has been deleted. Check the credentials or create a new automation client in the SitecoreAI Deploy portal"
+	case e.Code == "access_denied":
+		return "The automation client is not allowed to access the SitecoreAI Deploy API. Use an organization automation client, or check that the client has not been revoked"
+	case e.Code == "invalid_grant":
+		return "The credentials have expired or been revoked. If using Sitecore CLI authentication, run 'dotnet sitecore cloud login' again"
+	case e.StatusCode == 429:
+		return "Too many token requests. Consider enabling the token cache with token_cache = true or SITECOREAI_TOKEN_CACHE=1"
+	}
+
+	return ""
+}
+
+// describeCredentialSource returns a human readable description of a credential source
+func describeCredentialSource(credentialSource string) string {
+	switch credentialSource {
+	case CredentialSourceConfig:
+		return "the provider configuration (client_id and client_secret)"
+	case CredentialSourceEnv:
+		return "the environment variables SITECOREAI_CLIENT_ID and SITECOREAI_CLIENT_SECRET"
+	case CredentialSourceCLI:
+		return "the Sitecore CLI user.json"
+	}
+
+	return credentialSource
+}
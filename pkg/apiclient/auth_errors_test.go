@@ -0,0 +1,88 @@
+package apiclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthenticate_ReturnsAuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		AuthURL:          server.URL,
+		ClientID:         "test-client-id",
+		ClientSecret:     "wrong-secret",
+		CredentialSource: CredentialSourceEnv,
+		HTTPClient:       server.Client(),
+	}
+
+	err := client.Authenticate()
+	if err == nil {
+		t.Fatal("Expected authentication to fail")
+	}
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("Expected AuthError, got %T", err)
+	}
+
+	if authErr.Code != "invalid_client" {
+		t.Errorf("Expected code 'invalid_client', got '%s'", authErr.Code)
+	}
+
+	// The message should name the credential source and how to fix it
+	if !strings.Contains(err.Error(), "SITECOREAI_CLIENT_ID") {
+		t.Errorf("Expected error to name the credential source, got '%s'", err.Error())
+	}
+	if !strings.Contains(authErr.Remediation(), "client secret is wrong") {
+		t.Errorf("Expected remediation for the client credentials, got '%s'", authErr.Remediation())
+	}
+
+	// Wrapping by the API methods must keep the AuthError reachable
+	_, err = (&Client{
+		AuthURL:      server.URL,
+		ClientID:     "test-client-id",
+		ClientSecret: "wrong-secret",
+		HTTPClient:   server.Client(),
+	}).GetProjects()
+	if !errors.As(err, &authErr) {
+		t.Errorf("Expected AuthError from GetProjects, got '%v'", err)
+	}
+}
+
+func TestAuthError_Remediation(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		contains string
+	}{
+		{"invalid client", 401, `{"error": "invalid_client"}`, "client ID or client secret"},
+		{"access denied", 403, `{"error": "access_denied", "error_description": "Unauthorized"}`, "not allowed to access"},
+		{"wrong audience", 403, `{"error": "access_denied", "error_description": "Service not found: https://api.sitecorecloud.io"}`, "audience"},
+		{"rate limited", 429, `Too Many Requests`, "token cache"},
+		{"unknown", 500, `{"error": "server_error"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authErr := newAuthError(tt.status, []byte(tt.body), "")
+			remediation := authErr.Remediation()
+
+			if tt.contains == "" && remediation != "" {
+				t.Errorf("Expected no remediation, got '%s'", remediation)
+			}
+			if !strings.Contains(remediation, tt.contains) {
+				t.Errorf("Expected remediation to contain '%s', got '%s'", tt.contains, remediation)
+			}
+		})
+	}
+}
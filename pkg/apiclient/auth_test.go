@@ -1,8 +1,14 @@
 package apiclient
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -95,3 +101,65 @@ func TestFindCLIUserConfig(t *testing.T) {
 		t.Errorf("Expected host 'https://test-api.sitecorecloud.io/', got '%s'", config.Endpoints.XMCloud.Host)
 	}
 }
+
+func TestUnconfiguredClient(t *testing.T) {
+	client := NewUnconfiguredClient("configuration not known")
+
+	// No request may be sent, the configuration error is returned instead
+	_, err := client.GetProjects()
+	if err == nil {
+		t.Fatal("Expected GetProjects to fail for an unconfigured client")
+	}
+
+	if !strings.Contains(err.Error(), "configuration not known") {
+		t.Errorf("Expected error to contain the configuration reason, got '%v'", err)
+	}
+}
+
+func TestDoRequest_ConcurrentCallsRequestOneToken(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			tokenRequests.Add(1)
+			_, _ = fmt.Fprint(w, `{"access_token": "fresh-token", "token_type": "Bearer", "expires_in": 86400}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:      server.URL,
+		AuthURL:      server.URL,
+		ClientID:     "test-client-id",
+		ClientSecret: "test-client-secret",
+		HTTPClient:   server.Client(),
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetProjects()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetProjects failed: %v", err)
+		}
+	}
+	if requests := tokenRequests.Load(); requests != 1 {
+		t.Errorf("Expected 1 token request, got %d", requests)
+	}
+}
@@ -0,0 +1,128 @@
+package apiclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Credential sources describing where the provider found its credentials
+const (
+	CredentialSourceConfig = "config"
+	CredentialSourceEnv    = "env"
+	CredentialSourceCLI    = "cli"
+)
+
+// orgIDClaim is the custom claim holding the organization ID in Sitecore tokens
+const orgIDClaim = "https://auth.sitecorecloud.io/claims/org_id"
+
+// TokenClaims represents the claims of a Sitecore access token that are of interest
+type TokenClaims struct {
+	Subject        string          `json:"sub"`
+	AuthorizedApp  string          `json:"azp"`
+	ClientID       string          `json:"client_id"`
+	Audience       json.RawMessage `json:"aud"`
+	Scope          string          `json:"scope"`
+	Permissions    []string        `json:"permissions"`
+	Expiry         float64         `json:"exp"`
+	OrganizationID string          `json:"-"`
+}
+
+// CallerIdentity describes who the client is authenticated as
+type CallerIdentity struct {
+	OrganizationID   string
+	ClientID         string
+	Subject          string
+	Audience         []string
+	Scopes           []string
+	Permissions      []string
+	ExpiresAt        time.Time
+	CredentialSource string
+}
+
+// decodeTokenClaims decodes the payload of a JWT without verifying the signature
+func decodeTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+
+	// JWT uses unpadded base64url, but accept padded tokens as well
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	var claims TokenClaims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+
+	var custom map[string]interface{}
+	err = json.Unmarshal(payload, &custom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+
+	if orgID, ok := custom[orgIDClaim].(string); ok {
+		claims.OrganizationID = orgID
+	}
+
+	return &claims, nil
+}
+
+// audiences returns the aud claim which can be either a string or a list of strings
+func (t *TokenClaims) audiences() []string {
+	if len(t.Audience) == 0 {
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(t.Audience, &single); err == nil {
+		return []string{single}
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(t.Audience, &multiple); err == nil {
+		return multiple
+	}
+
+	return nil
+}
+
+// GetCallerIdentity returns the identity of the token used by the client
+func (c *Client) GetCallerIdentity() (*CallerIdentity, error) {
+	token, err := c.validToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure valid token: %w", err)
+	}
+
+	claims, err := decodeTokenClaims(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	clientID := claims.ClientID
+	if clientID == "" {
+		clientID = claims.AuthorizedApp
+	}
+
+	identity := &CallerIdentity{
+		OrganizationID:   claims.OrganizationID,
+		ClientID:         clientID,
+		Subject:          claims.Subject,
+		Audience:         claims.audiences(),
+		Scopes:           strings.Fields(claims.Scope),
+		Permissions:      claims.Permissions,
+		CredentialSource: c.CredentialSource,
+	}
+
+	if claims.Expiry > 0 {
+		identity.ExpiresAt = time.Unix(int64(claims.Expiry), 0).UTC()
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,88 @@
+package apiclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// makeTestToken builds an unsigned JWT with the given claims
+func makeTestToken(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+func TestGetCallerIdentity(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).Unix()
+
+	client := &Client{
+		CredentialSource: CredentialSourceEnv,
+		Token: makeTestToken(t, map[string]interface{}{
+			"sub":         "test-client-id@clients",
+			"azp":         "test-client-id",
+			"aud":         []string{"https://api.sitecorecloud.io", "https://other"},
+			"scope":       "xmcloud.cm:admin xmclouddeploy.projects:manage",
+			"permissions": []string{"xmcloud.cm:admin"},
+			"exp":         expiry,
+			orgIDClaim:    "org-1",
+		}),
+	}
+
+	identity, err := client.GetCallerIdentity()
+	if err != nil {
+		t.Fatalf("GetCallerIdentity failed: %v", err)
+	}
+
+	if identity.OrganizationID != "org-1" {
+		t.Errorf("Expected organization ID 'org-1', got '%s'", identity.OrganizationID)
+	}
+	if identity.ClientID != "test-client-id" {
+		t.Errorf("Expected client ID 'test-client-id', got '%s'", identity.ClientID)
+	}
+	if identity.Subject != "test-client-id@clients" {
+		t.Errorf("Expected subject 'test-client-id@clients', got '%s'", identity.Subject)
+	}
+	if len(identity.Audience) != 2 {
+		t.Errorf("Expected 2 audiences, got %v", identity.Audience)
+	}
+	if len(identity.Scopes) != 2 || identity.Scopes[1] != "xmclouddeploy.projects:manage" {
+		t.Errorf("Expected 2 scopes, got %v", identity.Scopes)
+	}
+	if len(identity.Permissions) != 1 {
+		t.Errorf("Expected 1 permission, got %v", identity.Permissions)
+	}
+	if identity.ExpiresAt.Unix() != expiry {
+		t.Errorf("Expected expiry %d, got %d", expiry, identity.ExpiresAt.Unix())
+	}
+	if identity.CredentialSource != CredentialSourceEnv {
+		t.Errorf("Expected credential source '%s', got '%s'", CredentialSourceEnv, identity.CredentialSource)
+	}
+}
+
+func TestDecodeTokenClaims_SingleAudience(t *testing.T) {
+	claims, err := decodeTokenClaims(makeTestToken(t, map[string]interface{}{
+		"sub": "auth0|user",
+		"aud": "https://api.sitecorecloud.io",
+	}))
+	if err != nil {
+		t.Fatalf("decodeTokenClaims failed: %v", err)
+	}
+
+	audiences := claims.audiences()
+	if len(audiences) != 1 || audiences[0] != "https://api.sitecorecloud.io" {
+		t.Errorf("Expected single audience, got %v", audiences)
+	}
+}
+
+func TestDecodeTokenClaims_InvalidToken(t *testing.T) {
+	_, err := decodeTokenClaims("not-a-jwt")
+	if err == nil {
+		t.Error("Expected error for invalid token")
+	}
+}
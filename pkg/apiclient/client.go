@@ -2,6 +2,7 @@ package apiclient
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -11,16 +12,49 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Client struct {
-	BaseURL      string
-	AuthURL      string
-	ClientID     string
-	ClientSecret string
-	CliConfig    *CLIUserConfig
-	Token        string
-	HTTPClient   *http.Client
+	BaseURL       string
+	AuthURL       string
+	ClientID      string
+	ClientSecret  string
+	CliConfig     *CLIUserConfig
+	CliConfigPath string
+	Token         string
+	TokenCache    *TokenCache
+	HTTPClient    *http.Client
+
+	// CredentialSource records where the credentials were found, see the CredentialSource constants
+	CredentialSource string
+
+	// ConfigError is returned from any API call when the client could not be configured yet
+	ConfigError error
+
+	// Timeouts are the default limits for long-running operations
+	Timeouts Timeouts
+
+	// tokenMutex guards Token, as Terraform shares the client between resources operated on in parallel
+	tokenMutex sync.Mutex
+}
+
+// Timeouts holds how long long-running operations, such as waiting for an
+// environment to be provisioned, may take before they are abandoned
+type Timeouts struct {
+	Create time.Duration
+	Read   time.Duration
+	Update time.Duration
+	Delete time.Duration
+}
+
+// DefaultTimeouts are used unless the provider configuration overrides them
+var DefaultTimeouts = Timeouts{
+	Create: 30 * time.Minute,
+	Read:   5 * time.Minute,
+	Update: 30 * time.Minute,
+	Delete: 30 * time.Minute,
 }
 
 // ErrorResponse represents the structure of error responses from the API
@@ -40,7 +74,12 @@ func NewClientFromCLI(configPath string) (*Client, error) {
 	if configPath == "" {
 		foundConfigPath, err := findCLIUserConfigPath()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get config path: %v", err)
+			return nil, fmt.Errorf("failed to get config path: %w", err)
+		}
+
+		if foundConfigPath == "" {
+			currentDir, _ := os.Getwd()
+			return nil, fmt.Errorf("no .sitecore/user.json found in %s or any parent directory, run 'dotnet sitecore cloud login' from the Terraform directory or one of its parents", currentDir)
 		}
 
 		configPath = foundConfigPath
@@ -78,7 +117,7 @@ func NewClientWithAllConfig(baseUrl string, authUrl string, clientId string, cli
 	if cliUserConfigPath != "" {
 		cfg, err := readCLIUserConfig(cliUserConfigPath)
 		if cfg == nil || err != nil {
-			return nil, fmt.Errorf("failed to read specified cli config from %s: %v", cliUserConfigPath, err)
+			return nil, fmt.Errorf("failed to read specified cli config from %s: %w", cliUserConfigPath, err)
 		}
 
 		cliConfig = cfg
@@ -99,15 +138,27 @@ func NewClientWithAllConfig(baseUrl string, authUrl string, clientId string, cli
 
 	setupProxy(httpClient)
 	return &Client{
-		BaseURL:      strings.TrimSuffix(BaseURL, "/"),
-		AuthURL:      strings.TrimSuffix(AuthURL, "/"),
-		ClientID:     clientId,
-		ClientSecret: clientSecret,
-		CliConfig:    cliConfig,
-		HTTPClient:   httpClient,
+		BaseURL:       strings.TrimSuffix(BaseURL, "/"),
+		AuthURL:       strings.TrimSuffix(AuthURL, "/"),
+		ClientID:      clientId,
+		ClientSecret:  clientSecret,
+		CliConfig:     cliConfig,
+		CliConfigPath: cliUserConfigPath,
+		HTTPClient:    httpClient,
+		Timeouts:      DefaultTimeouts,
 	}, nil
 }
 
+// NewUnconfiguredClient creates a client that fails every API call with the given reason.
+// It is used when the provider configuration is not known until apply.
+func NewUnconfiguredClient(reason string) *Client {
+	return &Client{
+		ConfigError: fmt.Errorf("%s", reason),
+		HTTPClient:  &http.Client{},
+		Timeouts:    DefaultTimeouts,
+	}
+}
+
 func setupProxy(client *http.Client) {
 	proxy := os.Getenv("SITECOREAI_PROXY")
 
@@ -124,19 +175,55 @@ func setupProxy(client *http.Client) {
 	}
 }
 
+// IsNotFoundError returns whether a request failed because the resource does not exist
+func IsNotFoundError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "request failed with status code 404")
+}
+
+// isResponseLost returns whether a request may have reached the API even though it failed,
+// because the connection failed or a gateway gave up waiting for the response
+func isResponseLost(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := err.Error()
+	return strings.Contains(message, "failed to send request") ||
+		strings.Contains(message, "request failed with status code 502") ||
+		strings.Contains(message, "request failed with status code 503") ||
+		strings.Contains(message, "request failed with status code 504")
+}
+
+// createdSince returns whether an object was created at or after the given time, allowing
+// for clock skew between the API and the provider. Unknown creation times never match
+func createdSince(createdAt string, since time.Time) bool {
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return false
+	}
+
+	return !created.Before(since.Add(-createRecoveryClockSkew))
+}
+
+// createRecoveryClockSkew is how far the API clock may be behind when recovering lost creates
+const createRecoveryClockSkew = time.Minute
+
 // doRequest handles the common request logic including authentication
 type RequestOptions struct {
 	Method string
 	Path   string
 	Body   interface{}
+
+	// Context cancels the request when done, defaults to context.Background()
+	Context context.Context
 }
 
 func (c *Client) doRequest(opts RequestOptions) (*http.Response, error) {
 
 	// Ensure we have a valid token
-	err := c.EnsureTokenValid()
+	token, err := c.validToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to ensure valid token: %v", err)
+		return nil, fmt.Errorf("failed to ensure valid token: %w", err)
 	}
 
 	// Create request URL
@@ -147,25 +234,30 @@ func (c *Client) doRequest(opts RequestOptions) (*http.Response, error) {
 	if opts.Body != nil {
 		jsonBody, err := json.Marshal(opts.Body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %v", err)
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	// Create HTTP request
-	req, err := http.NewRequest(opts.Method, requestURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, opts.Method, requestURL, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	req.Header.Set("Content-Type", "application/json")
 
 	// Send request
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
@@ -31,10 +31,22 @@ type ClientDto struct {
 	ClientID        string     `json:"clientId,omitempty"`
 	CreatedAt       string     `json:"createdAt,omitempty"`
 	ClientType      ClientType `json:"clientType,omitempty"`
+	ProjectID       string     `json:"projectId,omitempty"`
 	ProjectName     string     `json:"projectName,omitempty"`
+	EnvironmentID   string     `json:"environmentId,omitempty"`
 	EnvironmentName string     `json:"environmentName,omitempty"`
 }
 
+// BelongsTo returns whether the client was created for the environment. Older clients
+// only carry the project and environment names, so those are matched when there is no ID
+func (d ClientDto) BelongsTo(environment Environment) bool {
+	if d.EnvironmentID != "" {
+		return d.EnvironmentID == environment.ID
+	}
+
+	return d.EnvironmentName != "" && d.EnvironmentName == environment.Name && d.ProjectName == environment.ProjectName
+}
+
 // OrganizationClientDto represents an organization client in the response
 type OrganizationClientDto struct {
 	ID          string     `json:"id,omitempty"`
@@ -104,7 +116,7 @@ func (c *Client) CreateCMClient(projectID string, environmentID string, name str
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create CM client: %v", err)
+		return nil, fmt.Errorf("failed to create CM client: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -113,7 +125,7 @@ func (c *Client) CreateCMClient(projectID string, environmentID string, name str
 	var clientResponse ClientCreateResponse
 	err = json.NewDecoder(resp.Body).Decode(&clientResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode CM client response: %v", err)
+		return nil, fmt.Errorf("failed to decode CM client response: %w", err)
 	}
 
 	return &clientResponse, nil
@@ -138,7 +150,7 @@ func (c *Client) CreateEdgeClient(projectID string, environmentID string, name s
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Edge client: %v", err)
+		return nil, fmt.Errorf("failed to create Edge client: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -147,7 +159,7 @@ func (c *Client) CreateEdgeClient(projectID string, environmentID string, name s
 	var clientResponse ClientCreateResponse
 	err = json.NewDecoder(resp.Body).Decode(&clientResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode Edge client response: %v", err)
+		return nil, fmt.Errorf("failed to decode Edge client response: %w", err)
 	}
 
 	return &clientResponse, nil
@@ -170,7 +182,7 @@ func (c *Client) CreateDeployClient(name string, description string) (*ClientCre
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Deploy client: %v", err)
+		return nil, fmt.Errorf("failed to create Deploy client: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -179,7 +191,7 @@ func (c *Client) CreateDeployClient(name string, description string) (*ClientCre
 	var clientResponse ClientCreateResponse
 	err = json.NewDecoder(resp.Body).Decode(&clientResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode Deploy client response: %v", err)
+		return nil, fmt.Errorf("failed to decode Deploy client response: %w", err)
 	}
 
 	return &clientResponse, nil
@@ -204,7 +216,7 @@ func (c *Client) CreateEditingHostBuildClient(projectID string, environmentID st
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Editing Host Build client: %v", err)
+		return nil, fmt.Errorf("failed to create Editing Host Build client: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -213,7 +225,7 @@ func (c *Client) CreateEditingHostBuildClient(projectID string, environmentID st
 	var clientResponse ClientCreateResponse
 	err = json.NewDecoder(resp.Body).Decode(&clientResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode Editing Host Build client response: %v", err)
+		return nil, fmt.Errorf("failed to decode Editing Host Build client response: %w", err)
 	}
 
 	return &clientResponse, nil
@@ -230,7 +242,7 @@ func (c *Client) DeleteClient(id string) error {
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to delete client: %v", err)
+		return fmt.Errorf("failed to delete client: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -249,7 +261,7 @@ func (c *Client) GetClientsForOrganization() (*OrganizationClientsListResponse,
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get organization clients: %v", err)
+		return nil, fmt.Errorf("failed to get organization clients: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -258,7 +270,7 @@ func (c *Client) GetClientsForOrganization() (*OrganizationClientsListResponse,
 	var response OrganizationClientsListResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode organization clients response: %v", err)
+		return nil, fmt.Errorf("failed to decode organization clients response: %w", err)
 	}
 
 	return &response, nil
@@ -275,7 +287,7 @@ func (c *Client) GetClientsForEnvironment() (*ClientsListResponse, error) {
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get environment clients: %v", err)
+		return nil, fmt.Errorf("failed to get environment clients: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -284,8 +296,40 @@ func (c *Client) GetClientsForEnvironment() (*ClientsListResponse, error) {
 	var response ClientsListResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode environment clients response: %v", err)
+		return nil, fmt.Errorf("failed to decode environment clients response: %w", err)
 	}
 
 	return &response, nil
 }
+
+// FindEnvironmentClient returns the environment client with the given ID or OAuth client ID, or nil when there is none
+func (c *Client) FindEnvironmentClient(id string) (*ClientDto, error) {
+	clients, err := c.GetClientsForEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, client := range clients.Items {
+		if client.ID == id || client.ClientID == id {
+			return &client, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// FindOrganizationClient returns the organization client with the given ID or OAuth client ID, or nil when there is none
+func (c *Client) FindOrganizationClient(id string) (*OrganizationClientDto, error) {
+	clients, err := c.GetClientsForOrganization()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, client := range clients.Items {
+		if client.ID == id || client.ClientID == id {
+			return &client, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -408,3 +408,32 @@ func TestGetClientsForEnvironment(t *testing.T) {
 		t.Errorf("Expected client type 'Edge', got raw value '%d'", response.Items[1].ClientType)
 	}
 }
+
+func TestFindEnvironmentClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"items": [{"id": "env-client-1", "clientId": "oauth-client-1"}, {"id": "env-client-2", "clientId": "oauth-client-2"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	for _, id := range []string{"env-client-2", "oauth-client-2"} {
+		found, err := client.FindEnvironmentClient(id)
+		if err != nil {
+			t.Fatalf("FindEnvironmentClient failed: %v", err)
+		}
+		if found == nil || found.ID != "env-client-2" {
+			t.Errorf("Expected client 'env-client-2' for '%s', got %+v", id, found)
+		}
+	}
+
+	found, err := client.FindEnvironmentClient("missing")
+	if err != nil || found != nil {
+		t.Errorf("Expected no client and no error, got %+v and %v", found, err)
+	}
+}
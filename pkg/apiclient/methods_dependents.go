@@ -0,0 +1,137 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// Dependents holds what has to be deleted before an environment or a project can be deleted
+type Dependents struct {
+	// Environments are the environments to delete, editing host environments come
+	// before the CM environments they are linked to
+	Environments []Environment
+
+	// Clients are the automation clients created for the environments
+	Clients []ClientDto
+}
+
+// EnvironmentNames returns a description of each environment, to tell users what blocks a deletion
+func (d *Dependents) EnvironmentNames() []string {
+	names := make([]string, 0, len(d.Environments))
+	for _, environment := range d.Environments {
+		names = append(names, fmt.Sprintf("%q (%s)", environment.Name, environment.ID))
+	}
+
+	return names
+}
+
+// GetEnvironmentDependents returns the editing host environments linked to a CM environment and
+// the clients of the environment and of those editing hosts. An environment that no longer
+// exists has no dependents
+func (c *Client) GetEnvironmentDependents(ctx context.Context, environmentID string) (*Dependents, error) {
+	environment, err := c.GetEnvironment(ctx, environmentID)
+	if IsNotFoundError(err) {
+		return &Dependents{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	environments, err := c.GetProjectEnvironments(environment.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only editing hosts that are linked to the environment depend on it
+	var linked []Environment
+	for _, candidate := range environments {
+		if !candidate.IsDeleted && candidate.Type == "eh" && candidate.EditingHostEnvironmentDetails.CmEnvironmentId == environmentID {
+			linked = append(linked, candidate)
+		}
+	}
+
+	clients, err := c.clientsOf(append([]Environment{*environment}, linked...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dependents{Environments: linked, Clients: clients}, nil
+}
+
+// GetProjectDependents returns the environments of a project, ordered so they can be deleted
+// one after the other, and the clients of those environments
+func (c *Client) GetProjectDependents(projectID string) (*Dependents, error) {
+	environments, err := c.GetProjectEnvironments(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Editing hosts have to go before the CM environments they are linked to
+	var editingHosts, others []Environment
+	for _, environment := range environments {
+		switch {
+		case environment.IsDeleted:
+		case environment.Type == "eh":
+			editingHosts = append(editingHosts, environment)
+		default:
+			others = append(others, environment)
+		}
+	}
+	ordered := append(editingHosts, others...)
+
+	clients, err := c.clientsOf(ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dependents{Environments: ordered, Clients: clients}, nil
+}
+
+// DeleteDependents deletes the clients and then the environments, in order, waiting for
+// each environment to be deleted before moving on to the next, until the context is done
+func (c *Client) DeleteDependents(ctx context.Context, dependents *Dependents) error {
+	for _, client := range dependents.Clients {
+		err := c.DeleteClient(client.ID)
+		if err != nil && !IsNotFoundError(err) {
+			return fmt.Errorf("failed to delete client %q: %w", client.Name, err)
+		}
+	}
+
+	for _, environment := range dependents.Environments {
+		err := c.DeleteEnvironment(ctx, environment.ID)
+		if err != nil && !IsNotFoundError(err) {
+			return fmt.Errorf("failed to delete environment %q: %w", environment.Name, err)
+		}
+
+		err = c.WaitForEnvironmentDeleted(ctx, environment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete environment %q: %w", environment.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// clientsOf returns the environment clients created for any of the environments
+func (c *Client) clientsOf(environments []Environment) ([]ClientDto, error) {
+	if len(environments) == 0 {
+		return nil, nil
+	}
+
+	response, err := c.GetClientsForEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []ClientDto
+	for _, client := range response.Items {
+		for _, environment := range environments {
+			if client.BelongsTo(environment) {
+				clients = append(clients, client)
+				break
+			}
+		}
+	}
+
+	return clients, nil
+}
@@ -0,0 +1,128 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// dependentsServer serves a project with a CM environment, a linked editing host, an unrelated
+// combined environment and their clients, and records the deletions
+func dependentsServer(t *testing.T, deleted *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			*deleted = append(*deleted, r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/api/projects/v2/project/environments":
+			_, _ = fmt.Fprint(w, `[
+				{"id": "cm", "name": "cm", "projectId": "project", "type": "cm"},
+				{"id": "eh", "name": "eh", "projectId": "project", "type": "eh", "editingHostEnvironmentDetails": {"cmEnvironmentId": "cm"}},
+				{"id": "combined", "name": "combined", "projectId": "project"},
+				{"id": "old", "name": "old", "projectId": "project", "isDeleted": true}
+			]`)
+		case r.URL.Path == "/api/clients/v1/environment":
+			_, _ = fmt.Fprint(w, `{"items": [
+				{"id": "cm-client", "name": "cm-client", "environmentId": "cm"},
+				{"id": "eh-client", "name": "eh-client", "environmentId": "eh"},
+				{"id": "other-client", "name": "other-client", "environmentId": "other"}
+			]}`)
+		case strings.HasPrefix(r.URL.Path, "/api/environments/v2/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/environments/v2/")
+			for _, path := range *deleted {
+				if strings.HasSuffix(path, "/"+id) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+			}
+			_, _ = fmt.Fprintf(w, `{"id": "%s", "projectId": "project", "type": "cm"}`, id)
+		default:
+			t.Errorf("Unexpected request '%s %s'", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetEnvironmentDependents(t *testing.T) {
+	var deleted []string
+	server := dependentsServer(t, &deleted)
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	dependents, err := client.GetEnvironmentDependents(context.Background(), "cm")
+	if err != nil {
+		t.Fatalf("GetEnvironmentDependents failed: %v", err)
+	}
+
+	if len(dependents.Environments) != 1 || dependents.Environments[0].ID != "eh" {
+		t.Errorf("Expected only the linked editing host, got %+v", dependents.Environments)
+	}
+	if len(dependents.Clients) != 2 {
+		t.Errorf("Expected the clients of the CM environment and the editing host, got %+v", dependents.Clients)
+	}
+}
+
+func TestGetEnvironmentDependents_DeletedEnvironment(t *testing.T) {
+	deleted := []string{"/api/environments/v1/cm"}
+	server := dependentsServer(t, &deleted)
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	dependents, err := client.GetEnvironmentDependents(context.Background(), "cm")
+	if err != nil {
+		t.Fatalf("GetEnvironmentDependents failed: %v", err)
+	}
+
+	if len(dependents.Environments) != 0 || len(dependents.Clients) != 0 {
+		t.Errorf("Expected no dependents for an environment that is gone, got %+v", dependents)
+	}
+}
+
+func TestDeleteProjectDependents(t *testing.T) {
+	var deleted []string
+	server := dependentsServer(t, &deleted)
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	dependents, err := client.GetProjectDependents("project")
+	if err != nil {
+		t.Fatalf("GetProjectDependents failed: %v", err)
+	}
+
+	err = client.DeleteDependents(context.Background(), dependents)
+	if err != nil {
+		t.Fatalf("DeleteDependents failed: %v", err)
+	}
+
+	expected := []string{
+		"/api/clients/v1/cm-client",
+		"/api/clients/v1/eh-client",
+		"/api/environments/v1/eh",
+		"/api/environments/v1/cm",
+		"/api/environments/v1/combined",
+	}
+	if strings.Join(deleted, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected deletions %v, got %v", expected, deleted)
+	}
+}
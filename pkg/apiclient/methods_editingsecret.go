@@ -22,7 +22,7 @@ func (c *Client) ObtainEditingSecret(environmentID string) (string, error) {
 			// Return empty string for 404 as the editing secret will not be available until there have been a deployment.
 			return "", nil
 		}
-		return "", fmt.Errorf("failed to obtain editing secret: %v", err)
+		return "", fmt.Errorf("failed to obtain editing secret: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -31,7 +31,7 @@ func (c *Client) ObtainEditingSecret(environmentID string) (string, error) {
 	// Read the response body as plain text
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read editing secret response: %v", err)
+		return "", fmt.Errorf("failed to read editing secret response: %w", err)
 	}
 
 	// Trim whitespace and return as ApiKey
@@ -31,7 +31,7 @@ func (c *Client) GetEnvironmentVariables(environmentID string) ([]EnvironmentVar
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get environment variables: %v", err)
+		return nil, fmt.Errorf("failed to get environment variables: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -40,7 +40,7 @@ func (c *Client) GetEnvironmentVariables(environmentID string) ([]EnvironmentVar
 	var variables []EnvironmentVariable
 	err = json.NewDecoder(resp.Body).Decode(&variables)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode environment variables: %v", err)
+		return nil, fmt.Errorf("failed to decode environment variables: %w", err)
 	}
 
 	return variables, nil
@@ -58,7 +58,7 @@ func (c *Client) SetEnvironmentVariable(environmentID string, variableName strin
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to set environment variable: %v", err)
+		return fmt.Errorf("failed to set environment variable: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -77,7 +77,7 @@ func (c *Client) DeleteEnvironmentVariable(environmentID string, variableName st
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to delete environment variable: %v", err)
+		return fmt.Errorf("failed to delete environment variable: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
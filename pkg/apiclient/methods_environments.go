@@ -1,6 +1,7 @@
 package apiclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -22,7 +23,7 @@ type Environment struct {
 	PlatformTenantName             string                        `json:"platformTenantName,omitempty"`
 	RepositoryBranch               string                        `json:"repositoryBranch,omitempty"`
 	TenantType                     string                        `json:"tenantType,omitempty"`
-	ProvisioningStatus             int                           `json:"provisioningStatus,omitempty"`
+	ProvisioningStatus             ProvisioningStatus            `json:"provisioningStatus,omitempty"`
 	ProvisioningLastFailureMessage string                        `json:"provisioningLastFailureMessage,omitempty"`
 	DeployOnCommit                 bool                          `json:"deployOnCommit,omitempty"`
 	LastSuccessfulDeploymentId     string                        `json:"lastSuccessfulDeploymentId,omitempty"`
@@ -31,6 +32,8 @@ type Environment struct {
 	LastUpdatedBy                  string                        `json:"lastUpdatedBy,omitempty"`
 	LastUpdatedAt                  string                        `json:"lastUpdatedAt,omitempty"`
 	IsDeleted                      bool                          `json:"isDeleted,omitempty"`
+	DeletedAt                      string                        `json:"deletedAt,omitempty"`
+	DeletedBy                      string                        `json:"deletedBy,omitempty"`
 	PreviewContextId               string                        `json:"previewContextId,omitempty"`
 	LiveContextId                  string                        `json:"liveContextId,omitempty"`
 	HighAvailabilityEnabled        bool                          `json:"highAvailabilityEnabled,omitempty"`
@@ -38,6 +41,14 @@ type Environment struct {
 	EditingHostEnvironmentDetails  EditingHostEnvironmentDetails `json:"editingHostEnvironmentDetails,omitempty"`
 }
 
+// SitecoreVersion returns the version of Sitecore running in the environment
+func (e *Environment) SitecoreVersion() SitecoreVersion {
+	return SitecoreVersion{
+		MajorVersion: e.SitecoreMajorVersion,
+		MinorVersion: e.SitecoreMinorVersion,
+	}
+}
+
 type CreateEnvironmentRequest struct {
 	Name                          string                         `json:"name"`
 	TenantType                    int                            `json:"tenantType,omitempty"`
@@ -45,13 +56,82 @@ type CreateEnvironmentRequest struct {
 	RepositoryBranch              string                         `json:"repositoryBranch,omitempty"`
 	SitecoreMajorVersion          int                            `json:"sitecoreMajorVersion,omitempty"`
 	DeployOnCommit                bool                           `json:"deployOnCommit,omitempty"`
+	HighAvailabilityEnabled       bool                           `json:"highAvailabilityEnabled,omitempty"`
+	Zone                          string                         `json:"zone,omitempty"`
 	EditingHostEnvironmentDetails *EditingHostEnvironmentDetails `json:"editingHostEnvironmentDetails,omitempty"`
 }
 
+// UpdateEnvironmentRequest holds the changes to an environment, fields left nil are not sent
+type UpdateEnvironmentRequest struct {
+	Name                    *string `json:"name,omitempty"`
+	TenantType              *string `json:"tenantType,omitempty"`
+	RepositoryBranch        *string `json:"repositoryBranch,omitempty"`
+	SitecoreMajorVersion    *int    `json:"sitecoreMajorVersion,omitempty"`
+	DeployOnCommit          *bool   `json:"deployOnCommit,omitempty"`
+	HighAvailabilityEnabled *bool   `json:"highAvailabilityEnabled,omitempty"`
+}
+
+// UpgradeEnvironmentRequest is the body of an environment upgrade
+type UpgradeEnvironmentRequest struct {
+	SitecoreMajorVersion int `json:"sitecoreMajorVersion"`
+	SitecoreMinorVersion int `json:"sitecoreMinorVersion"`
+}
+
+// EnvironmentSettings holds the optional settings of a new environment
+type EnvironmentSettings struct {
+	RepositoryBranch        string
+	SitecoreMajorVersion    int
+	DeployOnCommit          bool
+	HighAvailabilityEnabled bool
+
+	// Zone is the region the environment is hosted in, see GetRegions
+	Zone string
+}
+
 type EditingHostEnvironmentDetails struct {
 	CmEnvironmentId string `json:"cmEnvironmentId,omitempty"`
 }
 
+// ProvisioningStatus is the provisioning state of an environment as returned by the Deploy API
+type ProvisioningStatus int
+
+const (
+	ProvisioningStatusNotStarted ProvisioningStatus = 0
+	ProvisioningStatusInProgress ProvisioningStatus = 1
+	ProvisioningStatusComplete   ProvisioningStatus = 2
+	ProvisioningStatusFailed     ProvisioningStatus = 3
+)
+
+// String returns the name used for the provisioning status in the Terraform schema
+func (s ProvisioningStatus) String() string {
+	switch s {
+	case ProvisioningStatusNotStarted:
+		return "not_started"
+	case ProvisioningStatusInProgress:
+		return "in_progress"
+	case ProvisioningStatusComplete:
+		return "complete"
+	case ProvisioningStatusFailed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+// ProvisioningFailedError is returned when waiting for an environment whose provisioning failed
+type ProvisioningFailedError struct {
+	EnvironmentID string
+	Message       string
+}
+
+func (e *ProvisioningFailedError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("provisioning of environment %s failed", e.EnvironmentID)
+	}
+
+	return fmt.Sprintf("provisioning of environment %s failed: %s", e.EnvironmentID, e.Message)
+}
+
 type EnvironmentType int
 
 const (
@@ -60,8 +140,10 @@ const (
 	EnvironmentTypeEhOnly   EnvironmentType = 2
 )
 
-// CreateEnvironment creates a new environment for a project using v2 API
-func (c *Client) CreateEnvironment(projectID string, name string, isProd bool, environmentType EnvironmentType, cmEnvironmentId string) (*Environment, error) {
+// CreateEnvironment creates a new environment for a project using v2 API. When the response is lost,
+// an environment with the same name that was created since the request was sent is returned instead
+func (c *Client) CreateEnvironment(ctx context.Context, projectID string, name string, isProd bool, environmentType EnvironmentType, cmEnvironmentId string, settings EnvironmentSettings) (*Environment, error) {
+	startTime := time.Now()
 
 	tenantType := 0
 	if isProd {
@@ -79,9 +161,14 @@ func (c *Client) CreateEnvironment(projectID string, name string, isProd bool, e
 	}
 
 	body := CreateEnvironmentRequest{
-		Name:       name,
-		TenantType: tenantType,
-		Type:       envType,
+		Name:                    name,
+		TenantType:              tenantType,
+		Type:                    envType,
+		RepositoryBranch:        settings.RepositoryBranch,
+		SitecoreMajorVersion:    settings.SitecoreMajorVersion,
+		DeployOnCommit:          settings.DeployOnCommit,
+		HighAvailabilityEnabled: settings.HighAvailabilityEnabled,
+		Zone:                    settings.Zone,
 	}
 
 	if cmEnvironmentId != "" {
@@ -92,15 +179,23 @@ func (c *Client) CreateEnvironment(projectID string, name string, isProd bool, e
 
 	// Create request options for v2 API
 	opts := RequestOptions{
-		Method: "POST",
-		Path:   fmt.Sprintf("/api/projects/v2/%s/environments", projectID),
-		Body:   body,
+		Method:  "POST",
+		Path:    fmt.Sprintf("/api/projects/v2/%s/environments", projectID),
+		Body:    body,
+		Context: ctx,
 	}
 
 	// Make the request
 	resp, err := c.doRequest(opts)
+	if isResponseLost(err) {
+		// The environment may have been created even though the response did not arrive
+		existing, findErr := c.FindProjectEnvironment(projectID, name)
+		if findErr == nil && existing != nil && createdSince(existing.CreatedAt, startTime) {
+			return existing, nil
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create environment: %v", err)
+		return nil, fmt.Errorf("failed to create environment: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -109,7 +204,7 @@ func (c *Client) CreateEnvironment(projectID string, name string, isProd bool, e
 	var createdEnvironment Environment
 	err = json.NewDecoder(resp.Body).Decode(&createdEnvironment)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode created environment: %v", err)
+		return nil, fmt.Errorf("failed to decode created environment: %w", err)
 	}
 
 	return &createdEnvironment, nil
@@ -117,17 +212,18 @@ func (c *Client) CreateEnvironment(projectID string, name string, isProd bool, e
 
 // DeleteEnvironment deletes an existing environment
 // Note: Using v1 API since v2 API doesn't have a DELETE endpoint for environments
-func (c *Client) DeleteEnvironment(environmentID string) error {
+func (c *Client) DeleteEnvironment(ctx context.Context, environmentID string) error {
 	// Create request options for v1 API (v2 doesn't support environment deletion)
 	opts := RequestOptions{
-		Method: "DELETE",
-		Path:   fmt.Sprintf("/api/environments/v1/%s", environmentID),
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/api/environments/v1/%s", environmentID),
+		Context: ctx,
 	}
 
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to delete environment: %v", err)
+		return fmt.Errorf("failed to delete environment: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -146,7 +242,7 @@ func (c *Client) GetProjectEnvironments(projectID string) ([]Environment, error)
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project environments: %v", err)
+		return nil, fmt.Errorf("failed to get project environments: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -155,25 +251,121 @@ func (c *Client) GetProjectEnvironments(projectID string) ([]Environment, error)
 	var environments []Environment
 	err = json.NewDecoder(resp.Body).Decode(&environments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode project environments: %v", err)
+		return nil, fmt.Errorf("failed to decode project environments: %w", err)
 	}
 
 	return environments, nil
 }
 
-// UpdateEnvironment updates an existing environment
-func (c *Client) UpdateEnvironment(projectID string, environmentID string, environment Environment) error {
+// FindProjectEnvironment returns the environment of a project with the given name, or nil when there is none
+func (c *Client) FindProjectEnvironment(projectID string, name string) (*Environment, error) {
+	environments, err := c.GetProjectEnvironments(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, environment := range environments {
+		if !environment.IsDeleted && environment.Name == name {
+			return &environment, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetLinkedCMEnvironmentID returns the ID of the CM environment an editing host environment is
+// linked to, or an empty string when it is not linked. Not every response for a single environment
+// includes the link, so the listing of the project is used when it is missing
+func (c *Client) GetLinkedCMEnvironmentID(environment *Environment) (string, error) {
+	if environment.EditingHostEnvironmentDetails.CmEnvironmentId != "" {
+		return environment.EditingHostEnvironmentDetails.CmEnvironmentId, nil
+	}
+
+	environments, err := c.GetProjectEnvironments(environment.ProjectID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, listed := range environments {
+		if listed.ID == environment.ID {
+			return listed.EditingHostEnvironmentDetails.CmEnvironmentId, nil
+		}
+	}
+
+	return "", nil
+}
+
+// GetDeletedProjectEnvironments lists the soft deleted environments of a project, which can still be restored
+func (c *Client) GetDeletedProjectEnvironments(projectID string) ([]Environment, error) {
+	// Create request options for v2 API
+	opts := RequestOptions{
+		Method: "GET",
+		Path:   fmt.Sprintf("/api/projects/v2/%s/environments?includeDeleted=true", projectID),
+	}
+
+	// Make the request
+	resp, err := c.doRequest(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted project environments: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Parse the response
+	var environments []Environment
+	err = json.NewDecoder(resp.Body).Decode(&environments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode deleted project environments: %w", err)
+	}
+
+	// Only keep the deleted environments
+	deleted := make([]Environment, 0, len(environments))
+	for _, environment := range environments {
+		if environment.IsDeleted {
+			deleted = append(deleted, environment)
+		}
+	}
+
+	return deleted, nil
+}
+
+// RestoreEnvironment restores a soft deleted environment and waits, until the context is done,
+// for it to be ready again
+func (c *Client) RestoreEnvironment(ctx context.Context, environmentID string) (*Environment, error) {
+	// Create request options
+	opts := RequestOptions{
+		Method:  "POST",
+		Path:    fmt.Sprintf("/api/environments/v1/%s/restore", environmentID),
+		Context: ctx,
+	}
+
+	// Make the request
+	resp, err := c.doRequest(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore environment: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	return c.waitForEnvironment(ctx, environmentID, "environment to be restored", func(environment *Environment) bool {
+		return !environment.IsDeleted && ((environment.PreviewContextId != "" && environment.LiveContextId != "") || (environment.Type == "eh"))
+	})
+}
+
+// UpdateEnvironment updates an existing environment, sending only the fields set in the request
+func (c *Client) UpdateEnvironment(ctx context.Context, environmentID string, update UpdateEnvironmentRequest) error {
 	// Create request options
 	opts := RequestOptions{
-		Method: "PUT",
-		Path:   fmt.Sprintf("/api/environments/v2/%s", environmentID),
-		Body:   environment,
+		Method:  "PUT",
+		Path:    fmt.Sprintf("/api/environments/v2/%s", environmentID),
+		Body:    update,
+		Context: ctx,
 	}
 
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to update environment: %v", err)
+		return fmt.Errorf("failed to update environment: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -181,18 +373,50 @@ func (c *Client) UpdateEnvironment(projectID string, environmentID string, envir
 	return nil
 }
 
+// PatchEnvironment applies the changes to an environment and returns the updated environment.
+// Only the changed fields are sent, except for the name which the API requires on every update.
+// When the name is not changed, the current environment is read first to send its current name.
+// Without any changes nothing is sent, and the current environment is returned
+func (c *Client) PatchEnvironment(ctx context.Context, environmentID string, changes UpdateEnvironmentRequest) (*Environment, error) {
+	if changes == (UpdateEnvironmentRequest{}) {
+		return c.GetEnvironment(ctx, environmentID)
+	}
+
+	update := changes
+	if update.Name == nil {
+		current, err := c.GetEnvironment(ctx, environmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read environment before update: %w", err)
+		}
+		update.Name = &current.Name
+	}
+
+	err := c.UpdateEnvironment(ctx, environmentID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := c.GetEnvironment(ctx, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment after update: %w", err)
+	}
+
+	return updated, nil
+}
+
 // GetEnvironment gets a specific environment by ID using v2 API
-func (c *Client) GetEnvironment(environmentID string) (*Environment, error) {
+func (c *Client) GetEnvironment(ctx context.Context, environmentID string) (*Environment, error) {
 	// Create request options for v2 API
 	opts := RequestOptions{
-		Method: "GET",
-		Path:   fmt.Sprintf("/api/environments/v2/%s", environmentID),
+		Method:  "GET",
+		Path:    fmt.Sprintf("/api/environments/v2/%s", environmentID),
+		Context: ctx,
 	}
 
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get environment: %v", err)
+		return nil, fmt.Errorf("failed to get environment: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -201,39 +425,152 @@ func (c *Client) GetEnvironment(environmentID string) (*Environment, error) {
 	var environment Environment
 	err = json.NewDecoder(resp.Body).Decode(&environment)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode environment: %v", err)
+		return nil, fmt.Errorf("failed to decode environment: %w", err)
 	}
 
 	return &environment, nil
 }
 
-// WaitForEnvironmentReady waits for an environment to be ready
-func (c *Client) WaitForEnvironmentReady(environmentID string, timeoutMinutes int) (*Environment, error) {
-	// Set timeout
-	timeout := time.Duration(timeoutMinutes) * time.Minute
+// WaitForEnvironmentReady waits for an environment to be ready, until the context is done.
+// If provisioning fails the environment is returned together with a ProvisioningFailedError
+func (c *Client) WaitForEnvironmentReady(ctx context.Context, environmentID string) (*Environment, error) {
+	return c.waitForEnvironment(ctx, environmentID, "environment to be ready", func(environment *Environment) bool {
+		// Check if environment has the required context IDs
+		return (environment.PreviewContextId != "" && environment.LiveContextId != "") || (environment.Type == "eh")
+	})
+}
+
+// waitForEnvironment polls an environment until settled returns true or the context is done.
+// Polling stops right away when provisioning has failed, in which case the environment is
+// returned together with a ProvisioningFailedError
+func (c *Client) waitForEnvironment(ctx context.Context, environmentID string, waitingFor string, settled func(*Environment) bool) (*Environment, error) {
 	startTime := time.Now()
 
 	// Polling interval
 	pollInterval := 1 * time.Second
 
 	for {
-		// Check if we've exceeded the timeout
-		if time.Since(startTime) > timeout {
-			return nil, fmt.Errorf("timed out waiting for environment to be ready after %d minutes", timeoutMinutes)
+		// Get the current environment status
+		environment, err := c.GetEnvironment(ctx, environmentID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("timed out waiting for %s after %s: %v", waitingFor, time.Since(startTime).Round(time.Second), ctx.Err())
+			}
+			return nil, fmt.Errorf("failed to get environment status: %w", err)
 		}
 
+		// Stop right away when provisioning has failed, it will not recover
+		if environment.ProvisioningStatus == ProvisioningStatusFailed {
+			return environment, &ProvisioningFailedError{
+				EnvironmentID: environmentID,
+				Message:       environment.ProvisioningLastFailureMessage,
+			}
+		}
+
+		if settled(environment) {
+			return environment, nil
+		}
+
+		// Wait before polling again
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for %s after %s: %v", waitingFor, time.Since(startTime).Round(time.Second), ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// WaitForEnvironmentDeleted waits for an environment to be gone or marked as deleted, until the context is done
+func (c *Client) WaitForEnvironmentDeleted(ctx context.Context, environmentID string) error {
+	startTime := time.Now()
+
+	// Polling interval
+	pollInterval := 1 * time.Second
+
+	for {
 		// Get the current environment status
-		environment, err := c.GetEnvironment(environmentID)
+		environment, err := c.GetEnvironment(ctx, environmentID)
+		if IsNotFoundError(err) {
+			return nil
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to get environment status: %v", err)
+			if ctx.Err() != nil {
+				return fmt.Errorf("timed out waiting for environment to be deleted after %s: %v", time.Since(startTime).Round(time.Second), ctx.Err())
+			}
+			return fmt.Errorf("failed to get environment status: %w", err)
 		}
 
-		// Check if environment has the required context IDs
-		if (environment.PreviewContextId != "" && environment.LiveContextId != "") || (environment.Type == "eh") {
-			return environment, nil
+		if environment.IsDeleted {
+			return nil
 		}
 
 		// Wait before polling again
-		time.Sleep(pollInterval)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for environment to be deleted after %s: %v", time.Since(startTime).Round(time.Second), ctx.Err())
+		case <-time.After(pollInterval):
+		}
 	}
 }
+
+// SetHighAvailability enables or disables high availability on an environment and waits,
+// until the context is done, for the change to settle
+func (c *Client) SetHighAvailability(ctx context.Context, environmentID string, enabled bool) (*Environment, error) {
+	environment, err := c.GetEnvironment(ctx, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment status: %w", err)
+	}
+
+	if environment.HighAvailabilityEnabled != enabled {
+		_, err = c.PatchEnvironment(ctx, environmentID, UpdateEnvironmentRequest{
+			HighAvailabilityEnabled: &enabled,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to change high availability: %w", err)
+		}
+	}
+
+	return c.WaitForHighAvailability(ctx, environmentID, enabled)
+}
+
+// WaitForHighAvailability waits for high availability to be enabled or disabled and for
+// the environment to finish provisioning the change, until the context is done
+func (c *Client) WaitForHighAvailability(ctx context.Context, environmentID string, enabled bool) (*Environment, error) {
+	return c.waitForEnvironment(ctx, environmentID, "high availability to change", func(environment *Environment) bool {
+		return environment.HighAvailabilityEnabled == enabled && environment.ProvisioningStatus != ProvisioningStatusInProgress
+	})
+}
+
+// UpgradeEnvironment starts the upgrade of an environment to another Sitecore version and waits,
+// until the context is done, for the upgrade to finish. A failed upgrade returns the environment
+// together with a ProvisioningFailedError
+func (c *Client) UpgradeEnvironment(ctx context.Context, environmentID string, version SitecoreVersion) (*Environment, error) {
+	// Create request options
+	opts := RequestOptions{
+		Method: "POST",
+		Path:   fmt.Sprintf("/api/environments/v1/%s/upgrade", environmentID),
+		Body: UpgradeEnvironmentRequest{
+			SitecoreMajorVersion: version.MajorVersion,
+			SitecoreMinorVersion: version.MinorVersion,
+		},
+		Context: ctx,
+	}
+
+	// Make the request
+	resp, err := c.doRequest(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade environment: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	return c.WaitForEnvironmentUpgrade(ctx, environmentID, version)
+}
+
+// WaitForEnvironmentUpgrade waits for an environment to run the Sitecore version and for
+// the environment to finish provisioning the upgrade, until the context is done
+func (c *Client) WaitForEnvironmentUpgrade(ctx context.Context, environmentID string, version SitecoreVersion) (*Environment, error) {
+	return c.waitForEnvironment(ctx, environmentID, "environment upgrade to finish", func(environment *Environment) bool {
+		return environment.SitecoreVersion().String() == version.String() && environment.ProvisioningStatus != ProvisioningStatusInProgress
+	})
+}
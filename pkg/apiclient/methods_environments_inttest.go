@@ -1,6 +1,7 @@
 package apiclient
 
 import (
+	"context"
 	"os"
 	"testing"
 )
@@ -87,7 +88,7 @@ func TestCreateEnvironment(t *testing.T) {
 	t.Logf("Testing with project: %s (ID: %s)", project.Name, project.ID)
 
 	// Create environment
-	env, err := client.CreateEnvironment(project.ID, testEnvironmentName, true, EnvironmentTypeCmOnly, "")
+	env, err := client.CreateEnvironment(context.Background(), project.ID, testEnvironmentName, true, EnvironmentTypeCmOnly, "", EnvironmentSettings{})
 	if err != nil {
 		t.Errorf("CreateEnvironment failed: %v", err)
 	}
@@ -155,7 +156,7 @@ func TestDeleteEnvironment(t *testing.T) {
 	}
 
 	// Delete environment
-	err = client.DeleteEnvironment(foundEnvironment.ID)
+	err = client.DeleteEnvironment(context.Background(), foundEnvironment.ID)
 	if err != nil {
 		t.Errorf("DeleteEnvironment failed: %v", err)
 	}
@@ -1,13 +1,16 @@
 package apiclient
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreateEnvironment_EditingHostEnvironmentDetails(t *testing.T) {
@@ -43,7 +46,7 @@ func TestCreateEnvironment_EditingHostEnvironmentDetails(t *testing.T) {
 		}
 
 		// Call CreateEnvironment without cmEnvironmentId
-		createdEnv, err := client.CreateEnvironment("test-project-id", "test-environment", false, EnvironmentTypeCombined, "")
+		createdEnv, err := client.CreateEnvironment(context.Background(), "test-project-id", "test-environment", false, EnvironmentTypeCombined, "", EnvironmentSettings{})
 		if err != nil {
 			t.Fatalf("CreateEnvironment failed: %v", err)
 		}
@@ -107,7 +110,7 @@ func TestCreateEnvironment_EditingHostEnvironmentDetails(t *testing.T) {
 		}
 
 		// Call CreateEnvironment with cmEnvironmentId
-		createdEnv, err := client.CreateEnvironment("test-project-id", "test-environment", false, EnvironmentTypeCombined, "test-cm-env-id")
+		createdEnv, err := client.CreateEnvironment(context.Background(), "test-project-id", "test-environment", false, EnvironmentTypeCombined, "test-cm-env-id", EnvironmentSettings{})
 		if err != nil {
 			t.Fatalf("CreateEnvironment failed: %v", err)
 		}
@@ -145,3 +148,588 @@ func TestCreateEnvironment_EditingHostEnvironmentDetails(t *testing.T) {
 		}
 	})
 }
+
+func TestWaitForEnvironmentReady(t *testing.T) {
+	t.Run("Returns the environment once it has context IDs", func(t *testing.T) {
+		polls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			polls++
+			w.WriteHeader(http.StatusOK)
+			if polls < 2 {
+				_, _ = fmt.Fprint(w, `{"id": "test-environment-id"}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"id": "test-environment-id", "previewContextId": "preview", "liveContextId": "live"}`)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			Token:      "test-token",
+		}
+
+		environment, err := client.WaitForEnvironmentReady(context.Background(), "test-environment-id")
+		if err != nil {
+			t.Fatalf("WaitForEnvironmentReady failed: %v", err)
+		}
+
+		if environment.LiveContextId != "live" {
+			t.Errorf("Expected live context ID 'live', got '%s'", environment.LiveContextId)
+		}
+	})
+
+	t.Run("Stops when the context deadline is exceeded", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprint(w, `{"id": "test-environment-id"}`)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			Token:      "test-token",
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := client.WaitForEnvironmentReady(ctx, "test-environment-id")
+		if err == nil {
+			t.Fatal("Expected an error when the context deadline is exceeded")
+		}
+
+		if !strings.Contains(err.Error(), "timed out waiting for environment to be ready") {
+			t.Errorf("Expected a timeout error, got: %v", err)
+		}
+	})
+}
+
+func TestWaitForEnvironmentReady_ProvisioningFailed(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"id": "test-environment-id", "provisioningStatus": 3, "provisioningLastFailureMessage": "quota exceeded"}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.WaitForEnvironmentReady(context.Background(), "test-environment-id")
+
+	var provisioningErr *ProvisioningFailedError
+	if !errors.As(err, &provisioningErr) {
+		t.Fatalf("Expected a ProvisioningFailedError, got: %v", err)
+	}
+	if provisioningErr.Message != "quota exceeded" {
+		t.Errorf("Expected failure message 'quota exceeded', got '%s'", provisioningErr.Message)
+	}
+	if environment == nil || environment.ProvisioningStatus.String() != "failed" {
+		t.Errorf("Expected the failed environment to be returned, got %+v", environment)
+	}
+	if polls != 1 {
+		t.Errorf("Expected to stop polling after the failure, polled %d times", polls)
+	}
+}
+
+func TestWaitForEnvironmentDeleted(t *testing.T) {
+	t.Run("Returns when the environment is gone", func(t *testing.T) {
+		polls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			polls++
+			if polls < 2 {
+				w.WriteHeader(http.StatusOK)
+				_, _ = fmt.Fprint(w, `{"id": "test-environment-id"}`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"title": "Not Found", "status": 404}`)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			Token:      "test-token",
+		}
+
+		err := client.WaitForEnvironmentDeleted(context.Background(), "test-environment-id")
+		if err != nil {
+			t.Fatalf("WaitForEnvironmentDeleted failed: %v", err)
+		}
+		if polls != 2 {
+			t.Errorf("Expected 2 polls, got %d", polls)
+		}
+	})
+
+	t.Run("Returns when the environment is marked as deleted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprint(w, `{"id": "test-environment-id", "isDeleted": true}`)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			Token:      "test-token",
+		}
+
+		err := client.WaitForEnvironmentDeleted(context.Background(), "test-environment-id")
+		if err != nil {
+			t.Fatalf("WaitForEnvironmentDeleted failed: %v", err)
+		}
+	})
+
+	t.Run("Stops when the context deadline is exceeded", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprint(w, `{"id": "test-environment-id"}`)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			Token:      "test-token",
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := client.WaitForEnvironmentDeleted(ctx, "test-environment-id")
+		if err == nil || !strings.Contains(err.Error(), "timed out waiting for environment to be deleted") {
+			t.Errorf("Expected a timeout error, got: %v", err)
+		}
+	})
+}
+
+func TestCreateEnvironment_Settings(t *testing.T) {
+	var requestData map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&requestData)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"id": "test-environment-id"}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	_, err := client.CreateEnvironment(context.Background(), "test-project-id", "test-environment", false, EnvironmentTypeCmOnly, "", EnvironmentSettings{
+		RepositoryBranch:     "main",
+		SitecoreMajorVersion: 1,
+		DeployOnCommit:       true,
+	})
+	if err != nil {
+		t.Fatalf("CreateEnvironment failed: %v", err)
+	}
+
+	if requestData["repositoryBranch"] != "main" {
+		t.Errorf("Expected repositoryBranch 'main', got '%v'", requestData["repositoryBranch"])
+	}
+	if requestData["sitecoreMajorVersion"] != float64(1) {
+		t.Errorf("Expected sitecoreMajorVersion 1, got '%v'", requestData["sitecoreMajorVersion"])
+	}
+	if requestData["deployOnCommit"] != true {
+		t.Errorf("Expected deployOnCommit true, got '%v'", requestData["deployOnCommit"])
+	}
+}
+
+func TestPatchEnvironment(t *testing.T) {
+	var requestData map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			_ = json.NewDecoder(r.Body).Decode(&requestData)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"id": "test-environment-id", "projectId": "test-project-id", "name": "test-environment", "repositoryBranch": "main", "deployOnCommit": true}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	deployOnCommit := false
+	_, err := client.PatchEnvironment(context.Background(), "test-environment-id", UpdateEnvironmentRequest{
+		DeployOnCommit: &deployOnCommit,
+	})
+	if err != nil {
+		t.Fatalf("PatchEnvironment failed: %v", err)
+	}
+
+	// A boolean can be set back to false
+	if requestData["deployOnCommit"] != false {
+		t.Errorf("Expected deployOnCommit false to be sent, got '%v'", requestData["deployOnCommit"])
+	}
+
+	// Only the change is sent, together with the current name the API requires
+	if len(requestData) != 2 || requestData["name"] != "test-environment" {
+		t.Errorf("Expected only the change and the current name to be sent, got %+v", requestData)
+	}
+}
+
+func TestPatchEnvironment_WithoutChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected no update without changes, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"id": "test-environment-id", "name": "test-environment"}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.PatchEnvironment(context.Background(), "test-environment-id", UpdateEnvironmentRequest{})
+	if err != nil {
+		t.Fatalf("PatchEnvironment failed: %v", err)
+	}
+	if environment.Name != "test-environment" {
+		t.Errorf("Expected the current environment, got %+v", environment)
+	}
+}
+
+func TestUpdateEnvironment_OnlySendsSetFields(t *testing.T) {
+	var requestData map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&requestData)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	name := "renamed"
+	err := client.UpdateEnvironment(context.Background(), "test-environment-id", UpdateEnvironmentRequest{
+		Name: &name,
+	})
+	if err != nil {
+		t.Fatalf("UpdateEnvironment failed: %v", err)
+	}
+
+	if len(requestData) != 1 || requestData["name"] != "renamed" {
+		t.Errorf("Expected only the name to be sent, got %+v", requestData)
+	}
+}
+
+func TestSetHighAvailability(t *testing.T) {
+	enabled := false
+	polls := 0
+	var requestData map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			_ = json.NewDecoder(r.Body).Decode(&requestData)
+			enabled = true
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		// Report the change as in progress on the first poll after the update
+		status := 2
+		if enabled {
+			polls++
+			if polls < 3 {
+				status = 1
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"id": "test-environment-id", "highAvailabilityEnabled": %t, "provisioningStatus": %d}`, enabled, status)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.SetHighAvailability(context.Background(), "test-environment-id", true)
+	if err != nil {
+		t.Fatalf("SetHighAvailability failed: %v", err)
+	}
+
+	if requestData["highAvailabilityEnabled"] != true {
+		t.Errorf("Expected highAvailabilityEnabled true to be sent, got '%v'", requestData["highAvailabilityEnabled"])
+	}
+	if !environment.HighAvailabilityEnabled || environment.ProvisioningStatus != ProvisioningStatusComplete {
+		t.Errorf("Expected to wait until high availability has settled, got %+v", environment)
+	}
+}
+
+func TestUpgradeEnvironment(t *testing.T) {
+	upgraded := false
+	polls := 0
+	var requestData map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			if r.URL.Path != "/api/environments/v1/test-environment-id/upgrade" {
+				t.Errorf("Expected path '/api/environments/v1/test-environment-id/upgrade', got '%s'", r.URL.Path)
+			}
+			_ = json.NewDecoder(r.Body).Decode(&requestData)
+			upgraded = true
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		// Keep the old version while the upgrade is in progress
+		minorVersion, status := 4, 2
+		if upgraded {
+			polls++
+			status = 1
+			if polls >= 3 {
+				minorVersion, status = 5, 2
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"id": "test-environment-id", "sitecoreMajorVersion": 1, "sitecoreMinorVersion": %d, "provisioningStatus": %d}`, minorVersion, status)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.UpgradeEnvironment(context.Background(), "test-environment-id", SitecoreVersion{MajorVersion: 1, MinorVersion: 5})
+	if err != nil {
+		t.Fatalf("UpgradeEnvironment failed: %v", err)
+	}
+
+	if requestData["sitecoreMajorVersion"] != float64(1) || requestData["sitecoreMinorVersion"] != float64(5) {
+		t.Errorf("Expected version 1.5 to be sent, got %+v", requestData)
+	}
+	if environment.SitecoreVersion().String() != "1.5" || polls < 3 {
+		t.Errorf("Expected to wait until the upgrade has finished, got %+v after %d polls", environment, polls)
+	}
+}
+
+func TestUpgradeEnvironment_Failed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.Method == "GET" {
+			_, _ = fmt.Fprint(w, `{"id": "test-environment-id", "sitecoreMajorVersion": 1, "sitecoreMinorVersion": 4, "provisioningStatus": 3, "provisioningLastFailureMessage": "upgrade failed"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.UpgradeEnvironment(context.Background(), "test-environment-id", SitecoreVersion{MajorVersion: 1, MinorVersion: 5})
+
+	var provisioningErr *ProvisioningFailedError
+	if !errors.As(err, &provisioningErr) {
+		t.Fatalf("Expected a ProvisioningFailedError, got %v", err)
+	}
+	if provisioningErr.Message != "upgrade failed" {
+		t.Errorf("Expected failure message 'upgrade failed', got '%s'", provisioningErr.Message)
+	}
+	if environment == nil || environment.SitecoreVersion().String() != "1.4" {
+		t.Errorf("Expected the environment to be returned on failure, got %+v", environment)
+	}
+}
+
+func TestGetDeletedProjectEnvironments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/projects/v2/test-project-id/environments" || r.URL.Query().Get("includeDeleted") != "true" {
+			t.Errorf("Unexpected request '%s'", r.URL.String())
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `[{"id": "active"}, {"id": "deleted", "isDeleted": true, "deletedAt": "2026-01-02T03:04:05Z"}]`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environments, err := client.GetDeletedProjectEnvironments("test-project-id")
+	if err != nil {
+		t.Fatalf("GetDeletedProjectEnvironments failed: %v", err)
+	}
+
+	if len(environments) != 1 || environments[0].ID != "deleted" || environments[0].DeletedAt != "2026-01-02T03:04:05Z" {
+		t.Errorf("Expected only the deleted environment, got %+v", environments)
+	}
+}
+
+func TestRestoreEnvironment(t *testing.T) {
+	restored := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			if r.URL.Path != "/api/environments/v1/test-environment-id/restore" {
+				t.Errorf("Expected path '/api/environments/v1/test-environment-id/restore', got '%s'", r.URL.Path)
+			}
+			restored = true
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"id": "test-environment-id", "isDeleted": %t, "previewContextId": "preview", "liveContextId": "live"}`, !restored)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.RestoreEnvironment(context.Background(), "test-environment-id")
+	if err != nil {
+		t.Fatalf("RestoreEnvironment failed: %v", err)
+	}
+
+	if !restored || environment.IsDeleted {
+		t.Errorf("Expected the environment to be restored, got %+v", environment)
+	}
+}
+
+func TestCreateEnvironment_RecoversLostResponse(t *testing.T) {
+	tests := map[string]struct {
+		createdAt string
+		recovered bool
+	}{
+		"created by the lost request": {createdAt: time.Now().UTC().Format(time.RFC3339), recovered: true},
+		"created before the request":  {createdAt: "2020-01-02T03:04:05Z", recovered: false},
+		"unknown creation time":       {createdAt: "", recovered: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" {
+					w.WriteHeader(http.StatusGatewayTimeout)
+					return
+				}
+
+				if r.URL.Path != "/api/projects/v2/test-project-id/environments" {
+					t.Errorf("Expected path '/api/projects/v2/test-project-id/environments', got '%s'", r.URL.Path)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = fmt.Fprintf(w, `[
+					{"id": "other", "name": "other-environment", "createdAt": %q},
+					{"id": "test-environment-id", "name": "test-environment", "createdAt": %q}
+				]`, test.createdAt, test.createdAt)
+			}))
+			defer server.Close()
+
+			client := &Client{
+				BaseURL:    server.URL,
+				HTTPClient: server.Client(),
+				Token:      "test-token",
+			}
+
+			environment, err := client.CreateEnvironment(context.Background(), "test-project-id", "test-environment", false, EnvironmentTypeCombined, "", EnvironmentSettings{})
+			if test.recovered {
+				if err != nil {
+					t.Fatalf("CreateEnvironment failed: %v", err)
+				}
+				if environment.ID != "test-environment-id" {
+					t.Errorf("Expected the environment created by the lost request, got %+v", environment)
+				}
+			} else if err == nil {
+				t.Errorf("Expected an error, got %+v", environment)
+			}
+		})
+	}
+}
+
+func TestFindProjectEnvironment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `[
+			{"id": "deleted", "name": "test-environment", "isDeleted": true},
+			{"id": "test-environment-id", "name": "test-environment"}
+		]`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	environment, err := client.FindProjectEnvironment("test-project-id", "test-environment")
+	if err != nil {
+		t.Fatalf("FindProjectEnvironment failed: %v", err)
+	}
+	if environment == nil || environment.ID != "test-environment-id" {
+		t.Errorf("Expected the environment that is not deleted, got %+v", environment)
+	}
+
+	environment, err = client.FindProjectEnvironment("test-project-id", "missing")
+	if err != nil || environment != nil {
+		t.Errorf("Expected no environment and no error, got %+v and %v", environment, err)
+	}
+}
+
+func TestGetLinkedCMEnvironmentID(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/projects/v2/test-project-id/environments" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `[
+			{"id": "test-cm-environment-id", "name": "cm", "type": "cm"},
+			{"id": "test-eh-environment-id", "name": "eh", "type": "eh", "editingHostEnvironmentDetails": {"cmEnvironmentId": "test-cm-environment-id"}}
+		]`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	// The link in the environment itself is used without another request
+	linked := &Environment{ID: "test-eh-environment-id", ProjectID: "test-project-id"}
+	linked.EditingHostEnvironmentDetails.CmEnvironmentId = "test-cm-environment-id"
+	cmEnvironmentID, err := client.GetLinkedCMEnvironmentID(linked)
+	if err != nil || cmEnvironmentID != "test-cm-environment-id" || requests != 0 {
+		t.Errorf("Expected the link of the environment without requests, got %q, %v and %d requests", cmEnvironmentID, err, requests)
+	}
+
+	// A missing link is taken from the project environments
+	cmEnvironmentID, err = client.GetLinkedCMEnvironmentID(&Environment{ID: "test-eh-environment-id", ProjectID: "test-project-id"})
+	if err != nil || cmEnvironmentID != "test-cm-environment-id" {
+		t.Errorf("Expected the link from the project environments, got %q and %v", cmEnvironmentID, err)
+	}
+
+	cmEnvironmentID, err = client.GetLinkedCMEnvironmentID(&Environment{ID: "test-cm-environment-id", ProjectID: "test-project-id"})
+	if err != nil || cmEnvironmentID != "" {
+		t.Errorf("Expected no link, got %q and %v", cmEnvironmentID, err)
+	}
+}
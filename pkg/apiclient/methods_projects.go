@@ -3,16 +3,26 @@ package apiclient
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // Project represents a Sitecore project
 // This struct should be updated based on the actual API response structure
 type Project struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Zone        string `json:"zone,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
 	// Add other project fields as needed based on API specification
 }
 
+// UpdateProjectRequest holds the changes to a project, fields left nil are not sent
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
 // GetProjects retrieves all projects
 func (c *Client) GetProjects() ([]Project, error) {
 	// Create request options
@@ -24,7 +34,7 @@ func (c *Client) GetProjects() ([]Project, error) {
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get projects: %v", err)
+		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -33,7 +43,7 @@ func (c *Client) GetProjects() ([]Project, error) {
 	var projects []Project
 	err = json.NewDecoder(resp.Body).Decode(&projects)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode projects: %v", err)
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
 	}
 
 	return projects, nil
@@ -50,7 +60,7 @@ func (c *Client) GetProject(projectID string) (*Project, error) {
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project: %v", err)
+		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -59,14 +69,33 @@ func (c *Client) GetProject(projectID string) (*Project, error) {
 	var project Project
 	err = json.NewDecoder(resp.Body).Decode(&project)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode project: %v", err)
+		return nil, fmt.Errorf("failed to decode project: %w", err)
 	}
 
 	return &project, nil
 }
 
-// CreateProject creates a new project
+// FindProject returns the project with the given name, or nil when there is none
+func (c *Client) FindProject(name string) (*Project, error) {
+	projects, err := c.GetProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		if project.Name == name {
+			return &project, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateProject creates a new project. When the response is lost, a project with
+// the same name that was created since the request was sent is returned instead
 func (c *Client) CreateProject(project Project) (*Project, error) {
+	startTime := time.Now()
+
 	// Create request options
 	opts := RequestOptions{
 		Method: "POST",
@@ -76,8 +105,15 @@ func (c *Client) CreateProject(project Project) (*Project, error) {
 
 	// Make the request
 	resp, err := c.doRequest(opts)
+	if isResponseLost(err) {
+		// The project may have been created even though the response did not arrive
+		existing, findErr := c.FindProject(project.Name)
+		if findErr == nil && existing != nil && createdSince(existing.CreatedAt, startTime) {
+			return existing, nil
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create project: %v", err)
+		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -86,25 +122,25 @@ func (c *Client) CreateProject(project Project) (*Project, error) {
 	var createdProject Project
 	err = json.NewDecoder(resp.Body).Decode(&createdProject)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode created project: %v", err)
+		return nil, fmt.Errorf("failed to decode created project: %w", err)
 	}
 
 	return &createdProject, nil
 }
 
-// UpdateProject updates an existing project
-func (c *Client) UpdateProject(projectID string, project Project) error {
+// UpdateProject updates an existing project, sending only the fields set in the request
+func (c *Client) UpdateProject(projectID string, update UpdateProjectRequest) error {
 	// Create request options
 	opts := RequestOptions{
 		Method: "PUT",
 		Path:   fmt.Sprintf("/api/projects/v1/%s", projectID),
-		Body:   project,
+		Body:   update,
 	}
 
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to update project: %v", err)
+		return fmt.Errorf("failed to update project: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -112,6 +148,37 @@ func (c *Client) UpdateProject(projectID string, project Project) error {
 	return nil
 }
 
+// PatchProject applies the changes to a project and returns the updated project.
+// Only the changed fields are sent, except for the name which the API requires on every update.
+// When the name is not changed, the current project is read first to send its current name.
+// Without any changes nothing is sent, and the current project is returned
+func (c *Client) PatchProject(projectID string, changes UpdateProjectRequest) (*Project, error) {
+	if changes == (UpdateProjectRequest{}) {
+		return c.GetProject(projectID)
+	}
+
+	update := changes
+	if update.Name == nil {
+		current, err := c.GetProject(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read project before update: %w", err)
+		}
+		update.Name = &current.Name
+	}
+
+	err := c.UpdateProject(projectID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := c.GetProject(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project after update: %w", err)
+	}
+
+	return updated, nil
+}
+
 // DeleteProject deletes a project
 func (c *Client) DeleteProject(projectID string) error {
 	// Create request options
@@ -123,7 +190,7 @@ func (c *Client) DeleteProject(projectID string) error {
 	// Make the request
 	resp, err := c.doRequest(opts)
 	if err != nil {
-		return fmt.Errorf("failed to delete project: %v", err)
+		return fmt.Errorf("failed to delete project: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -0,0 +1,118 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateProject_RecoversLostResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		if r.URL.Path != "/api/projects/v1" {
+			t.Errorf("Expected path '/api/projects/v1', got '%s'", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `[{"id": "test-project-id", "name": "test-project", "createdAt": %q}]`, time.Now().UTC().Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	project, err := client.CreateProject(Project{Name: "test-project"})
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	if project.ID != "test-project-id" {
+		t.Errorf("Expected the project created by the lost request, got %+v", project)
+	}
+}
+
+func TestCreateProject_DoesNotRecoverOnRejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		t.Errorf("Expected no lookup after a rejected create, got %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	if _, err := client.CreateProject(Project{Name: "test-project"}); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestPatchProject(t *testing.T) {
+	var requestData map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			_ = json.NewDecoder(r.Body).Decode(&requestData)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"id": "test-project-id", "name": "test-project", "description": "current description"}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	description := "new description"
+	_, err := client.PatchProject("test-project-id", UpdateProjectRequest{
+		Description: &description,
+	})
+	if err != nil {
+		t.Fatalf("PatchProject failed: %v", err)
+	}
+
+	// Only the change is sent, together with the current name the API requires
+	if len(requestData) != 2 || requestData["name"] != "test-project" || requestData["description"] != "new description" {
+		t.Errorf("Expected only the change and the current name to be sent, got %+v", requestData)
+	}
+}
+
+func TestPatchProject_WithoutChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected no update without changes, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"id": "test-project-id", "name": "test-project"}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	project, err := client.PatchProject("test-project-id", UpdateProjectRequest{})
+	if err != nil {
+		t.Fatalf("PatchProject failed: %v", err)
+	}
+	if project.Name != "test-project" {
+		t.Errorf("Expected the current project, got %+v", project)
+	}
+}
@@ -0,0 +1,39 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Region represents a region (zone) environments can be hosted in
+type Region struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	IsDefault   bool   `json:"isDefault,omitempty"`
+}
+
+// GetRegions lists the regions available to the organization
+func (c *Client) GetRegions() ([]Region, error) {
+	// Create request options
+	opts := RequestOptions{
+		Method: "GET",
+		Path:   "/api/regions/v1",
+	}
+
+	// Make the request
+	resp, err := c.doRequest(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get regions: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Parse the response
+	var regions []Region
+	err = json.NewDecoder(resp.Body).Decode(&regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode regions: %w", err)
+	}
+
+	return regions, nil
+}
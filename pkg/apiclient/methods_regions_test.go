@@ -0,0 +1,37 @@
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRegions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/regions/v1" {
+			t.Errorf("Expected path '/api/regions/v1', got '%s'", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `[{"name": "euw", "displayName": "West Europe", "isDefault": true}, {"name": "use", "displayName": "East US"}]`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	regions, err := client.GetRegions()
+	if err != nil {
+		t.Fatalf("GetRegions failed: %v", err)
+	}
+
+	if len(regions) != 2 {
+		t.Fatalf("Expected 2 regions, got %d", len(regions))
+	}
+	if regions[0].Name != "euw" || regions[0].DisplayName != "West Europe" || !regions[0].IsDefault {
+		t.Errorf("Unexpected first region: %+v", regions[0])
+	}
+}
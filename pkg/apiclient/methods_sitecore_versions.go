@@ -0,0 +1,66 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SitecoreVersion represents a version of Sitecore environments can run
+type SitecoreVersion struct {
+	MajorVersion int    `json:"majorVersion"`
+	MinorVersion int    `json:"minorVersion"`
+	DisplayName  string `json:"displayName,omitempty"`
+	IsDefault    bool   `json:"isDefault,omitempty"`
+}
+
+// String returns the version as major.minor
+func (v SitecoreVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.MajorVersion, v.MinorVersion)
+}
+
+// ParseSitecoreVersion parses a version in the major.minor format
+func ParseSitecoreVersion(version string) (SitecoreVersion, error) {
+	major, minor, found := strings.Cut(version, ".")
+	if !found {
+		return SitecoreVersion{}, fmt.Errorf("expected a version in the major.minor format, got '%s'", version)
+	}
+
+	majorVersion, err := strconv.Atoi(major)
+	if err != nil || majorVersion < 0 {
+		return SitecoreVersion{}, fmt.Errorf("invalid major version in '%s'", version)
+	}
+	minorVersion, err := strconv.Atoi(minor)
+	if err != nil || minorVersion < 0 {
+		return SitecoreVersion{}, fmt.Errorf("invalid minor version in '%s'", version)
+	}
+
+	return SitecoreVersion{MajorVersion: majorVersion, MinorVersion: minorVersion}, nil
+}
+
+// GetSitecoreVersions lists the Sitecore versions environments can be created with or upgraded to
+func (c *Client) GetSitecoreVersions() ([]SitecoreVersion, error) {
+	// Create request options
+	opts := RequestOptions{
+		Method: "GET",
+		Path:   "/api/sitecoreVersions/v1",
+	}
+
+	// Make the request
+	resp, err := c.doRequest(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Sitecore versions: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Parse the response
+	var versions []SitecoreVersion
+	err = json.NewDecoder(resp.Body).Decode(&versions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Sitecore versions: %w", err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,63 @@
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSitecoreVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/sitecoreVersions/v1" {
+			t.Errorf("Expected path '/api/sitecoreVersions/v1', got '%s'", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `[{"majorVersion": 1, "minorVersion": 4, "displayName": "1.4"}, {"majorVersion": 1, "minorVersion": 5, "displayName": "1.5", "isDefault": true}]`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+
+	versions, err := client.GetSitecoreVersions()
+	if err != nil {
+		t.Fatalf("GetSitecoreVersions failed: %v", err)
+	}
+
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(versions))
+	}
+	if versions[1].String() != "1.5" || !versions[1].IsDefault {
+		t.Errorf("Unexpected second version: %+v", versions[1])
+	}
+}
+
+func TestParseSitecoreVersion(t *testing.T) {
+	tests := map[string]struct {
+		version     string
+		expected    string
+		expectError bool
+	}{
+		"major and minor": {version: "1.5", expected: "1.5"},
+		"major only":      {version: "1", expectError: true},
+		"not a number":    {version: "1.x", expectError: true},
+		"negative":        {version: "-1.0", expectError: true},
+		"empty":           {version: "", expectError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, err := ParseSitecoreVersion(test.version)
+			if (err != nil) != test.expectError {
+				t.Fatalf("Expected error: %v, got %v", test.expectError, err)
+			}
+			if !test.expectError && version.String() != test.expected {
+				t.Errorf("Expected version '%s', got '%s'", test.expected, version.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// tokenExpiryMargin is how long before expiry a token is considered stale
+	tokenExpiryMargin = 5 * time.Minute
+
+	// lockRetryInterval and lockTimeout control how long we wait for another
+	// process holding the cache lock
+	lockRetryInterval = 100 * time.Millisecond
+	lockTimeout       = 10 * time.Second
+
+	// lockStaleAfter is when a lock file is assumed to be left behind by a
+	// process that crashed while holding it. It must be shorter than
+	// lockTimeout so waiting processes break the lock before giving up
+	lockStaleAfter = 5 * time.Second
+)
+
+// TokenCache stores access tokens on disk so they can be reused across
+// Terraform runs until shortly before they expire
+type TokenCache struct {
+	Dir string
+}
+
+// cachedToken represents the structure of a token cache file
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// NewTokenCache creates a token cache in the given directory. If dir is empty
+// SITECOREAI_TOKEN_CACHE_DIR is used, falling back to the user cache directory
+func NewTokenCache(dir string) (*TokenCache, error) {
+	if dir == "" {
+		dir = os.Getenv("SITECOREAI_TOKEN_CACHE_DIR")
+	}
+
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCacheDir, "sitecoreai", "tokens")
+	}
+
+	return &TokenCache{Dir: dir}, nil
+}
+
+// cacheKey builds the file name for a token from the values that identify it
+func cacheKey(authURL string, audience string, clientID string) string {
+	hash := sha256.Sum256([]byte(authURL + "\n" + audience + "\n" + clientID))
+	return hex.EncodeToString(hash[:]) + ".json"
+}
+
+// Get returns a cached token that is valid for at least tokenExpiryMargin,
+// or an empty string if there is none
+func (tc *TokenCache) Get(authURL string, audience string, clientID string) (string, error) {
+	unlock, err := tc.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(filepath.Join(tc.Dir, cacheKey(authURL, audience, clientID)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	var token cachedToken
+	err = json.Unmarshal(data, &token)
+	if err != nil {
+		// A corrupt cache entry is not fatal, it will be overwritten
+		return "", nil
+	}
+
+	if token.AccessToken == "" || time.Now().Add(tokenExpiryMargin).After(token.ExpiresAt) {
+		return "", nil
+	}
+
+	return token.AccessToken, nil
+}
+
+// Put stores a token in the cache with owner-only permissions
+func (tc *TokenCache) Put(authURL string, audience string, clientID string, accessToken string, expiresAt time.Time) error {
+	err := os.MkdirAll(tc.Dir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	unlock, err := tc.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := json.Marshal(cachedToken{
+		AccessToken: accessToken,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	// Write to a temporary file and rename so readers never see a partial file
+	tmpFile, err := os.CreateTemp(tc.Dir, "token-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create token cache file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	err = tmpFile.Chmod(0600)
+	if err == nil {
+		_, err = tmpFile.Write(data)
+	}
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write token cache file: %w", err)
+	}
+
+	err = os.Rename(tmpPath, filepath.Join(tc.Dir, cacheKey(authURL, audience, clientID)))
+	if err != nil {
+		return fmt.Errorf("failed to write token cache file: %w", err)
+	}
+
+	return nil
+}
+
+// lock acquires an exclusive lock on the cache directory so concurrent
+// Terraform processes do not read and write the cache at the same time.
+// A lock file is used instead of flock so it works on all platforms.
+func (tc *TokenCache) lock() (func(), error) {
+	lockPath := filepath.Join(tc.Dir, ".lock")
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = lockFile.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+
+		if errors.Is(err, os.ErrNotExist) {
+			// The cache directory does not exist yet, so there is nothing to lock
+			return func() {}, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock token cache: %w", err)
+		}
+
+		// Remove locks left behind by processes that did not clean up
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			_ = os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for token cache lock %s", lockPath)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,178 @@
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTokenCache_PutAndGet(t *testing.T) {
+	cache, err := NewTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTokenCache failed: %v", err)
+	}
+
+	err = cache.Put("https://auth.example.com", defaultAudience, "client-1", "token-1", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	token, err := cache.Get("https://auth.example.com", defaultAudience, "client-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if token != "token-1" {
+		t.Errorf("Expected token 'token-1', got '%s'", token)
+	}
+
+	// Tokens are keyed by auth URL, audience and client ID
+	token, err = cache.Get("https://auth.example.com", defaultAudience, "client-2")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if token != "" {
+		t.Errorf("Expected no token for other client ID, got '%s'", token)
+	}
+
+	// The lock file should be released
+	if _, err := os.Stat(filepath.Join(cache.Dir, ".lock")); !os.IsNotExist(err) {
+		t.Errorf("Expected lock file to be removed, got %v", err)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(cache.Dir, cacheKey("https://auth.example.com", defaultAudience, "client-1")))
+		if err != nil {
+			t.Fatalf("Failed to stat cache file: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected cache file permissions 0600, got %o", info.Mode().Perm())
+		}
+	}
+}
+
+func TestTokenCache_ExpiringTokenIsNotReturned(t *testing.T) {
+	cache, err := NewTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTokenCache failed: %v", err)
+	}
+
+	err = cache.Put("https://auth.example.com", defaultAudience, "client-1", "token-1", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	token, err := cache.Get("https://auth.example.com", defaultAudience, "client-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if token != "" {
+		t.Errorf("Expected token about to expire to be ignored, got '%s'", token)
+	}
+}
+
+func TestTokenCache_StaleLockIsRemoved(t *testing.T) {
+	cache, err := NewTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTokenCache failed: %v", err)
+	}
+
+	lockPath := filepath.Join(cache.Dir, ".lock")
+	if err := os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatalf("Failed to create lock file: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+
+	err = cache.Put("https://auth.example.com", defaultAudience, "client-1", "token-1", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Put failed with stale lock: %v", err)
+	}
+}
+
+func TestTokenCache_LeftoverLockIsBrokenBeforeTimeout(t *testing.T) {
+	if lockStaleAfter >= lockTimeout {
+		t.Fatalf("lockStaleAfter (%v) must be shorter than lockTimeout (%v)", lockStaleAfter, lockTimeout)
+	}
+
+	cache, err := NewTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTokenCache failed: %v", err)
+	}
+
+	// A lock left behind by a process that crashed just before, which is not stale yet
+	lockPath := filepath.Join(cache.Dir, ".lock")
+	if err := os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatalf("Failed to create lock file: %v", err)
+	}
+	recent := time.Now().Add(-lockStaleAfter + time.Second)
+	if err := os.Chtimes(lockPath, recent, recent); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+
+	start := time.Now()
+	err = cache.Put("https://auth.example.com", defaultAudience, "client-1", "token-1", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Put failed with leftover lock: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= lockTimeout {
+		t.Errorf("Put took %v, expected the lock to be broken before %v", elapsed, lockTimeout)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("Expected lock file to be removed after Put, got %v", err)
+	}
+}
+
+func TestAuthenticate_UsesTokenCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "fresh-token-%d", "token_type": "Bearer", "expires_in": 86400}`, requests)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+
+	newClient := func() *Client {
+		cache, err := NewTokenCache(cacheDir)
+		if err != nil {
+			t.Fatalf("NewTokenCache failed: %v", err)
+		}
+		return &Client{
+			AuthURL:      server.URL,
+			ClientID:     "test-client-id",
+			ClientSecret: "test-client-secret",
+			TokenCache:   cache,
+			HTTPClient:   server.Client(),
+		}
+	}
+
+	// First client requests a token and stores it
+	first := newClient()
+	if err := first.Authenticate(); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if first.Token != "fresh-token-1" {
+		t.Errorf("Expected token 'fresh-token-1', got '%s'", first.Token)
+	}
+
+	// Second client, as in a new Terraform run, reuses the cached token
+	second := newClient()
+	if err := second.Authenticate(); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if second.Token != "fresh-token-1" {
+		t.Errorf("Expected cached token 'fresh-token-1', got '%s'", second.Token)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 token request, got %d", requests)
+	}
+}
@@ -0,0 +1,109 @@
+// Adoption of existing projects and environments instead of creating new ones
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// adoptExistingAttribute returns the schema attribute to take over an existing remote object on create
+func adoptExistingAttribute(kind string, scope string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Whether to take over an existing " + kind + " with the same name " + scope + " when the resource is created, " +
+			"instead of failing because the name is already in use. Defaults to false",
+		Optional: true,
+	}
+}
+
+// plannedAdoption returns whether a new resource is planned to take over an existing remote object
+func plannedAdoption(ctx context.Context, req resource.ModifyPlanRequest, diagnostics *diag.Diagnostics) bool {
+	if !req.State.Raw.IsNull() {
+		return false
+	}
+
+	var adoptExisting types.Bool
+	diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+
+	return adoptExisting.ValueBool()
+}
+
+// adoptProject returns the existing project with the given name, or nil when there is none
+func adoptProject(client *apiclient.Client, name string, region types.String) (*apiclient.Project, error) {
+	project, err := client.FindProject(name)
+	if err != nil || project == nil {
+		return nil, err
+	}
+
+	if !region.IsNull() && !region.IsUnknown() && project.Zone != "" && project.Zone != region.ValueString() {
+		return nil, fmt.Errorf("project %q (%s) is hosted in region %s, not in %s", project.Name, project.ID, project.Zone, region.ValueString())
+	}
+
+	return project, nil
+}
+
+// adoptEnvironment returns the existing environment with the given name in the project, or nil when
+// there is none. The environment must match the configured type, CM environment, is_prod and region,
+// the configured settings are applied to it
+func adoptEnvironment(ctx context.Context, client *apiclient.Client, projectID string, name string, environmentType apiclient.EnvironmentType, cmEnvironmentID string, isProd types.Bool, region types.String, changes apiclient.UpdateEnvironmentRequest) (*apiclient.Environment, error) {
+	environment, err := client.FindProjectEnvironment(projectID, name)
+	if err != nil || environment == nil {
+		return nil, err
+	}
+
+	if !isEnvironmentType(environment, environmentType) {
+		return nil, fmt.Errorf("environment %q (%s) is a %s environment", environment.Name, environment.ID, environmentTypeName(environment))
+	}
+	if environmentType == apiclient.EnvironmentTypeEhOnly && environment.EditingHostEnvironmentDetails.CmEnvironmentId != cmEnvironmentID {
+		return nil, fmt.Errorf("editing host environment %q (%s) is linked to CM environment %s, not to %s", environment.Name, environment.ID, environment.EditingHostEnvironmentDetails.CmEnvironmentId, cmEnvironmentID)
+	}
+	// A mismatch would replace the adopted environment on the next apply, as changing is_prod replaces it
+	if prod := strings.EqualFold(environment.TenantType, "prod"); !isProd.IsUnknown() && environment.TenantType != "" && prod != isProd.ValueBool() {
+		return nil, fmt.Errorf("environment %q (%s) has tenant type %s, it can only be adopted with is_prod = %t", environment.Name, environment.ID, environment.TenantType, prod)
+	}
+	if !region.IsNull() && !region.IsUnknown() && environment.Zone != "" && environment.Zone != region.ValueString() {
+		return nil, fmt.Errorf("environment %q (%s) is hosted in region %s, not in %s", environment.Name, environment.ID, environment.Zone, region.ValueString())
+	}
+
+	if changes == (apiclient.UpdateEnvironmentRequest{}) {
+		return environment, nil
+	}
+
+	return client.PatchEnvironment(ctx, environment.ID, changes)
+}
+
+// isEnvironmentType returns whether an environment is of the given type
+func isEnvironmentType(environment *apiclient.Environment, environmentType apiclient.EnvironmentType) bool {
+	switch environmentType {
+	case apiclient.EnvironmentTypeCmOnly:
+		return environment.Type == "cm"
+	case apiclient.EnvironmentTypeEhOnly:
+		return environment.Type == "eh"
+	}
+
+	return environment.Type != "cm" && environment.Type != "eh"
+}
+
+// adoptedEnvironmentChanges returns the configured settings to apply to an adopted environment
+func adoptedEnvironmentChanges(repositoryBranch types.String, deployOnCommit types.Bool, sitecoreMajorVersion types.Int64) apiclient.UpdateEnvironmentRequest {
+	changes := apiclient.UpdateEnvironmentRequest{}
+	if !repositoryBranch.IsNull() && !repositoryBranch.IsUnknown() {
+		changes.RepositoryBranch = repositoryBranch.ValueStringPointer()
+	}
+	if !deployOnCommit.IsNull() && !deployOnCommit.IsUnknown() {
+		changes.DeployOnCommit = deployOnCommit.ValueBoolPointer()
+	}
+	if !sitecoreMajorVersion.IsNull() && !sitecoreMajorVersion.IsUnknown() {
+		majorVersion := int(sitecoreMajorVersion.ValueInt64())
+		changes.SitecoreMajorVersion = &majorVersion
+	}
+
+	return changes
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+func TestAdoptEnvironment(t *testing.T) {
+	tests := map[string]struct {
+		name            string
+		environmentType apiclient.EnvironmentType
+		isProd          types.Bool
+		expectID        string
+		expectError     string
+	}{
+		"existing environment": {
+			name:            "staging",
+			environmentType: apiclient.EnvironmentTypeCombined,
+			expectID:        "combined",
+		},
+		"no environment with the name": {
+			name:            "production",
+			environmentType: apiclient.EnvironmentTypeCombined,
+		},
+		"environment of another type": {
+			name:            "authoring",
+			environmentType: apiclient.EnvironmentTypeCombined,
+			expectError:     "is a CM environment",
+		},
+		"production environment": {
+			name:            "live",
+			environmentType: apiclient.EnvironmentTypeCombined,
+			isProd:          types.BoolValue(true),
+			expectID:        "live",
+		},
+		"production environment with is_prod false": {
+			name:            "live",
+			environmentType: apiclient.EnvironmentTypeCombined,
+			isProd:          types.BoolValue(false),
+			expectError:     "can only be adopted with is_prod = true",
+		},
+		"production environment without is_prod": {
+			name:            "live",
+			environmentType: apiclient.EnvironmentTypeCombined,
+			isProd:          types.BoolNull(),
+			expectError:     "can only be adopted with is_prod = true",
+		},
+		"non-production environment with is_prod true": {
+			name:            "staging",
+			environmentType: apiclient.EnvironmentTypeCombined,
+			isProd:          types.BoolValue(true),
+			expectError:     "can only be adopted with is_prod = false",
+		},
+	}
+
+	client := fixtureClient(t, fixtureRoutes{
+		"/api/projects/v2/project/environments": `[
+			{"id": "cm", "name": "authoring", "projectId": "project", "type": "cm"},
+			{"id": "combined", "name": "staging", "projectId": "project", "tenantType": "nonprod"},
+			{"id": "live", "name": "live", "projectId": "project", "tenantType": "prod"}
+		]`,
+	})
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			environment, err := adoptEnvironment(context.Background(), client, "project", test.name, test.environmentType, "", test.isProd, types.StringNull(), apiclient.UpdateEnvironmentRequest{})
+
+			if test.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectError) {
+					t.Errorf("Expected error containing '%s', got %v", test.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if test.expectID == "" && environment != nil {
+				t.Errorf("Expected no environment, got %+v", environment)
+			}
+			if test.expectID != "" && (environment == nil || environment.ID != test.expectID) {
+				t.Errorf("Expected environment '%s', got %+v", test.expectID, environment)
+			}
+		})
+	}
+}
+
+func TestAdoptedEnvironmentChanges(t *testing.T) {
+	changes := adoptedEnvironmentChanges(types.StringValue("main"), types.BoolUnknown(), types.Int64Null())
+
+	if changes.RepositoryBranch == nil || *changes.RepositoryBranch != "main" {
+		t.Errorf("Expected the configured repository branch, got %v", changes.RepositoryBranch)
+	}
+	if changes.DeployOnCommit != nil || changes.SitecoreMajorVersion != nil {
+		t.Errorf("Expected unconfigured settings to be left unchanged, got %+v", changes)
+	}
+}
@@ -0,0 +1,22 @@
+// Reporting of API client errors in diagnostics
+package provider
+
+import (
+	"errors"
+
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// errorDetail returns the message of an API client error for a diagnostic. Authentication
+// is deferred until the first API call, so credential errors surface in resources and data
+// sources rather than in the provider configuration, and get the hint on how to fix them
+func errorDetail(err error) string {
+	var authErr *apiclient.AuthError
+	if errors.As(err, &authErr) {
+		if remediation := authErr.Remediation(); remediation != "" {
+			return err.Error() + "\n\n" + remediation
+		}
+	}
+
+	return err.Error()
+}
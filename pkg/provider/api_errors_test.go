@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+func TestErrorDetail(t *testing.T) {
+	t.Run("adds remediation of wrapped auth errors", func(t *testing.T) {
+		authErr := &apiclient.AuthError{StatusCode: 401, Code: "invalid_client"}
+		err := fmt.Errorf("failed to get project: %w", fmt.Errorf("failed to ensure valid token: %w", authErr))
+
+		detail := errorDetail(err)
+		if !strings.HasPrefix(detail, err.Error()) {
+			t.Errorf("Expected detail to start with the error, got '%s'", detail)
+		}
+		if !strings.Contains(detail, authErr.Remediation()) {
+			t.Errorf("Expected detail to contain the remediation, got '%s'", detail)
+		}
+	})
+
+	t.Run("keeps other errors", func(t *testing.T) {
+		err := errors.New("request failed with status code 500: Internal Server Error")
+
+		if detail := errorDetail(err); detail != err.Error() {
+			t.Errorf("Expected detail '%s', got '%s'", err.Error(), detail)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -36,6 +37,7 @@ func (r *baseEnvironmentVariableResource) Configure(ctx context.Context, req res
 // Schema defines the schema for the base resource.
 func (r *baseEnvironmentVariableResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Base environment variable resource.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -74,50 +76,22 @@ func (r *baseEnvironmentVariableResource) Create(ctx context.Context, req resour
 		return
 	}
 
-	// Validate mutual exclusivity of value and secret_value
-	if !plan.Value.IsNull() && !plan.SecretValue.IsNull() {
-		resp.Diagnostics.AddError(
-			"Invalid Attribute Combination",
-			"Either 'value' or 'secret_value' must be set, but not both.",
-		)
-		return
-	}
-	if plan.Value.IsNull() && plan.SecretValue.IsNull() {
-		resp.Diagnostics.AddError(
-			"Missing Required Attribute",
-			"Either 'value' or 'secret_value' must be set.",
-		)
-		return
-	}
-
-	// Validate that values are not empty
-	if !plan.Value.IsNull() && plan.Value.ValueString() == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Attribute Value",
-			"The 'value' attribute cannot be empty.",
-		)
-		return
-	}
-	if !plan.SecretValue.IsNull() && plan.SecretValue.ValueString() == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Attribute Value",
-			"The 'secret_value' attribute cannot be empty.",
-		)
+	r.create(&plan, target, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	requestBody := apiclient.EnvironmentVariableUpsertRequestBodyDto{
-		Value:  plan.Value.ValueString(),
-		Secret: false,
-		Target: &target,
-	}
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	setIdentity(ctx, resp.Identity, variableIdentityModel{EnvironmentID: plan.EnvironmentID, Name: plan.Name}, &resp.Diagnostics)
+}
 
-	if !plan.SecretValue.IsNull() {
-		requestBody = apiclient.EnvironmentVariableUpsertRequestBodyDto{
-			Value:  plan.SecretValue.ValueString(),
-			Secret: true,
-			Target: &target,
-		}
+// create sets the environment variable and the ID of the model
+func (r *baseEnvironmentVariableResource) create(plan *baseEnvironmentVariableResourceModel, target string, diagnostics *diag.Diagnostics) {
+	requestBody, ok := variableRequestBody(plan, target, diagnostics)
+	if !ok {
+		return
 	}
 
 	// Set the environment variable using the API
@@ -127,9 +101,9 @@ func (r *baseEnvironmentVariableResource) Create(ctx context.Context, req resour
 		requestBody,
 	)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diagnostics.AddError(
 			"Error creating environment variable",
-			"Could not create environment variable, unexpected error: "+err.Error(),
+			"Could not create environment variable, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -137,10 +111,6 @@ func (r *baseEnvironmentVariableResource) Create(ctx context.Context, req resour
 	// Generate composite ID: environment_id:name
 	compositeID := fmt.Sprintf("%s:%s", plan.EnvironmentID.ValueString(), plan.Name.ValueString())
 	plan.ID = types.StringValue(compositeID)
-
-	// Set state to fully populated data
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -153,51 +123,22 @@ func (r *baseEnvironmentVariableResource) Update(ctx context.Context, req resour
 		return
 	}
 
-	// Validate mutual exclusivity of value and secret_value
-	if !plan.Value.IsNull() && !plan.SecretValue.IsNull() {
-		resp.Diagnostics.AddError(
-			"Invalid Attribute Combination",
-			"Either 'value' or 'secret_value' must be set, but not both.",
-		)
-		return
-	}
-	if plan.Value.IsNull() && plan.SecretValue.IsNull() {
-		resp.Diagnostics.AddError(
-			"Missing Required Attribute",
-			"Either 'value' or 'secret_value' must be set.",
-		)
-		return
-	}
-
-	// Validate that values are not empty
-	if !plan.Value.IsNull() && plan.Value.ValueString() == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Attribute Value",
-			"The 'value' attribute cannot be empty.",
-		)
-		return
-	}
-	if !plan.SecretValue.IsNull() && plan.SecretValue.ValueString() == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Attribute Value",
-			"The 'secret_value' attribute cannot be empty.",
-		)
+	r.update(&plan, target, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Prepare the request body
-	requestBody := apiclient.EnvironmentVariableUpsertRequestBodyDto{
-		Value:  plan.Value.ValueString(),
-		Secret: false,
-		Target: &target,
-	}
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	setIdentity(ctx, resp.Identity, variableIdentityModel{EnvironmentID: plan.EnvironmentID, Name: plan.Name}, &resp.Diagnostics)
+}
 
-	if !plan.SecretValue.IsNull() {
-		requestBody = apiclient.EnvironmentVariableUpsertRequestBodyDto{
-			Value:  plan.SecretValue.ValueString(),
-			Secret: true,
-			Target: &target,
-		}
+// update sets the environment variable to the planned value and sets the ID of the model
+func (r *baseEnvironmentVariableResource) update(plan *baseEnvironmentVariableResourceModel, target string, diagnostics *diag.Diagnostics) {
+	requestBody, ok := variableRequestBody(plan, target, diagnostics)
+	if !ok {
+		return
 	}
 
 	// Update the environment variable using the API
@@ -208,18 +149,18 @@ func (r *baseEnvironmentVariableResource) Update(ctx context.Context, req resour
 	)
 
 	if err != nil && strings.Contains(err.Error(), "request failed with status code 409: Conflict") {
-		resp.Diagnostics.AddWarning(
+		diagnostics.AddWarning(
 			"Got conflict during update but it will be handled",
-			fmt.Sprintf("Will recreate environment variable '%s' instead. The error was: %s", plan.Name.ValueString(), err.Error()),
+			fmt.Sprintf("Will recreate environment variable '%s' instead. The error was: %s", plan.Name.ValueString(), errorDetail(err)),
 		)
 		err = r.client.DeleteEnvironmentVariable(
 			plan.EnvironmentID.ValueString(),
 			plan.Name.ValueString(),
 		)
 		if err != nil {
-			resp.Diagnostics.AddWarning(
+			diagnostics.AddWarning(
 				"Got error while attempting to remove env var",
-				fmt.Sprintf("Will recreate environment variable '%s' but delete gave error: %s", plan.Name.ValueString(), err.Error()),
+				fmt.Sprintf("Will recreate environment variable '%s' but delete gave error: %s", plan.Name.ValueString(), errorDetail(err)),
 			)
 		}
 
@@ -231,9 +172,9 @@ func (r *baseEnvironmentVariableResource) Update(ctx context.Context, req resour
 	}
 
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diagnostics.AddError(
 			"Error updating environment variable",
-			"Could not update environment variable, unexpected error: "+err.Error(),
+			"Could not update environment variable, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -241,14 +182,59 @@ func (r *baseEnvironmentVariableResource) Update(ctx context.Context, req resour
 	// Generate composite ID: environment_id:name
 	compositeID := fmt.Sprintf("%s:%s", plan.EnvironmentID.ValueString(), plan.Name.ValueString())
 	plan.ID = types.StringValue(compositeID)
+}
 
-	// Set state to fully populated data
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
+// variableRequestBody validates the planned value and secret value and returns the request to set the variable
+func variableRequestBody(plan *baseEnvironmentVariableResourceModel, target string, diagnostics *diag.Diagnostics) (apiclient.EnvironmentVariableUpsertRequestBodyDto, bool) {
+	// Validate mutual exclusivity of value and secret_value
+	if !plan.Value.IsNull() && !plan.SecretValue.IsNull() {
+		diagnostics.AddError(
+			"Invalid Attribute Combination",
+			"Either 'value' or 'secret_value' must be set, but not both.",
+		)
+		return apiclient.EnvironmentVariableUpsertRequestBodyDto{}, false
+	}
+	if plan.Value.IsNull() && plan.SecretValue.IsNull() {
+		diagnostics.AddError(
+			"Missing Required Attribute",
+			"Either 'value' or 'secret_value' must be set.",
+		)
+		return apiclient.EnvironmentVariableUpsertRequestBodyDto{}, false
+	}
+
+	// Validate that values are not empty
+	if !plan.Value.IsNull() && plan.Value.ValueString() == "" {
+		diagnostics.AddError(
+			"Invalid Attribute Value",
+			"The 'value' attribute cannot be empty.",
+		)
+		return apiclient.EnvironmentVariableUpsertRequestBodyDto{}, false
+	}
+	if !plan.SecretValue.IsNull() && plan.SecretValue.ValueString() == "" {
+		diagnostics.AddError(
+			"Invalid Attribute Value",
+			"The 'secret_value' attribute cannot be empty.",
+		)
+		return apiclient.EnvironmentVariableUpsertRequestBodyDto{}, false
+	}
+
+	if !plan.SecretValue.IsNull() {
+		return apiclient.EnvironmentVariableUpsertRequestBodyDto{
+			Value:  plan.SecretValue.ValueString(),
+			Secret: true,
+			Target: &target,
+		}, true
+	}
+
+	return apiclient.EnvironmentVariableUpsertRequestBodyDto{
+		Value:  plan.Value.ValueString(),
+		Secret: false,
+		Target: &target,
+	}, true
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (r *baseEnvironmentVariableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+func (r *baseEnvironmentVariableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, target string) {
 	// Get current state
 	var state baseEnvironmentVariableResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -257,32 +243,45 @@ func (r *baseEnvironmentVariableResource) Read(ctx context.Context, req resource
 		return
 	}
 
+	found := r.read(&state, target, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		removeDeletedResource(ctx, resp, "Environment variable", state.ID.ValueString())
+		return
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	setIdentity(ctx, resp.Identity, variableIdentityModel{EnvironmentID: state.EnvironmentID, Name: state.Name}, &resp.Diagnostics)
+}
+
+// read refreshes the value of the environment variable with the given target in the model.
+// It returns false when the variable, or its environment, was deleted outside of Terraform
+func (r *baseEnvironmentVariableResource) read(state *baseEnvironmentVariableResourceModel, target string, diagnostics *diag.Diagnostics) bool {
 	// Get all environment variables from API
 	variables, err := r.client.GetEnvironmentVariables(state.EnvironmentID.ValueString())
+	if apiclient.IsNotFoundError(err) {
+		return false
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diagnostics.AddError(
 			"Error reading environment variables",
-			"Could not read environment variables: "+err.Error(),
+			"Could not read environment variables: "+errorDetail(err),
 		)
-		return
-	}
-
-	// Find our specific variable
-	var foundVariable *apiclient.EnvironmentVariable
-	for _, variable := range variables {
-		if variable.Name == state.Name.ValueString() {
-			foundVariable = &variable
-			break
-		}
+		return false
 	}
 
+	// Find our specific variable, variables with the same name can exist for several targets
+	foundVariable := findEnvironmentVariable(variables, state.Name.ValueString(), target)
 	if foundVariable == nil {
-		// Variable was deleted outside of Terraform
-		resp.State.RemoveResource(ctx)
-		return
+		return false
 	}
 
-	// Update the state based on whether the variable is a secret
+	// Update the state based on whether the variable is a secret, so
+	// imported configuration uses the attribute matching the variable
 	if foundVariable.Secret {
 		state.SecretValue = types.StringValue(foundVariable.Value)
 		state.Value = types.StringNull()
@@ -291,9 +290,29 @@ func (r *baseEnvironmentVariableResource) Read(ctx context.Context, req resource
 		state.SecretValue = types.StringNull()
 	}
 
-	// Set refreshed state
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	return true
+}
+
+// findEnvironmentVariable returns the variable with the name and target, or nil when there is none.
+// Without a target, the only variable with the name is returned whatever its target, as states and
+// imports without a target have always matched variables with one
+func findEnvironmentVariable(variables []apiclient.EnvironmentVariable, name string, target string) *apiclient.EnvironmentVariable {
+	var named []apiclient.EnvironmentVariable
+	for _, variable := range variables {
+		if variable.Name != name {
+			continue
+		}
+		if variable.Target == target {
+			return &variable
+		}
+		named = append(named, variable)
+	}
+
+	if target == "" && len(named) == 1 {
+		return &named[0]
+	}
+
+	return nil
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -306,15 +325,19 @@ func (r *baseEnvironmentVariableResource) Delete(ctx context.Context, req resour
 		return
 	}
 
-	// Delete the environment variable
+	r.delete(&state, &resp.Diagnostics)
+}
+
+// delete deletes the environment variable
+func (r *baseEnvironmentVariableResource) delete(state *baseEnvironmentVariableResourceModel, diagnostics *diag.Diagnostics) {
 	err := r.client.DeleteEnvironmentVariable(
 		state.EnvironmentID.ValueString(),
 		state.Name.ValueString(),
 	)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diagnostics.AddError(
 			"Error deleting environment variable",
-			"Could not delete environment variable, unexpected error: "+err.Error(),
+			"Could not delete environment variable, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -0,0 +1,147 @@
+// Caller identity data source implementation
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ datasource.DataSource              = &callerIdentityDataSource{}
+	_ datasource.DataSourceWithConfigure = &callerIdentityDataSource{}
+)
+
+// NewCallerIdentityDataSource is a helper function to simplify the provider implementation
+func NewCallerIdentityDataSource() datasource.DataSource {
+	return &callerIdentityDataSource{}
+}
+
+// callerIdentityDataSource is the data source implementation
+type callerIdentityDataSource struct {
+	client *apiclient.Client
+}
+
+// callerIdentityDataSourceModel maps the data source schema data
+type callerIdentityDataSourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	OrganizationID   types.String `tfsdk:"organization_id"`
+	ClientID         types.String `tfsdk:"client_id"`
+	Subject          types.String `tfsdk:"subject"`
+	Audience         types.List   `tfsdk:"audience"`
+	Scopes           types.List   `tfsdk:"scopes"`
+	Permissions      types.List   `tfsdk:"permissions"`
+	ExpiresAt        types.String `tfsdk:"expires_at"`
+	CredentialSource types.String `tfsdk:"credential_source"`
+}
+
+// Metadata returns the data source type name
+func (d *callerIdentityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_caller_identity"
+}
+
+// Schema defines the schema for the data source
+func (d *callerIdentityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Authentication ¤ Use this data source to get the identity the provider is authenticated as. The token itself is not exposed",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The subject of the token",
+				Computed:    true,
+			},
+			"organization_id": schema.StringAttribute{
+				Description: "The ID of the organization the token belongs to",
+				Computed:    true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "The ID of the automation client or application the token was issued to",
+				Computed:    true,
+			},
+			"subject": schema.StringAttribute{
+				Description: "The subject of the token, either an automation client or a user",
+				Computed:    true,
+			},
+			"audience": schema.ListAttribute{
+				Description: "The audiences of the token",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"scopes": schema.ListAttribute{
+				Description: "The scopes granted to the token",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"permissions": schema.ListAttribute{
+				Description: "The permissions granted to the token",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "When the token expires (RFC 3339)",
+				Computed:    true,
+			},
+			"credential_source": schema.StringAttribute{
+				Description: "Where the provider found its credentials, can have the values 'config', 'env' or 'cli'",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source
+func (d *callerIdentityDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*apiclient.Client)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (d *callerIdentityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Get caller identity from the token
+	identity, err := d.client.GetCallerIdentity()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading caller identity",
+			"Could not read caller identity: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Map the identity to the schema
+	var state callerIdentityDataSourceModel
+	state.ID = types.StringValue(identity.Subject)
+	state.OrganizationID = types.StringValue(identity.OrganizationID)
+	state.ClientID = types.StringValue(identity.ClientID)
+	state.Subject = types.StringValue(identity.Subject)
+	state.CredentialSource = types.StringValue(identity.CredentialSource)
+
+	state.ExpiresAt = types.StringNull()
+	if !identity.ExpiresAt.IsZero() {
+		state.ExpiresAt = types.StringValue(identity.ExpiresAt.Format(time.RFC3339))
+	}
+
+	var diags diag.Diagnostics
+	state.Audience, diags = types.ListValueFrom(ctx, types.StringType, identity.Audience)
+	resp.Diagnostics.Append(diags...)
+	state.Scopes, diags = types.ListValueFrom(ctx, types.StringType, identity.Scopes)
+	resp.Diagnostics.Append(diags...)
+	state.Permissions, diags = types.ListValueFrom(ctx, types.StringType, identity.Permissions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
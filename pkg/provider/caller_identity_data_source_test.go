@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestCallerIdentityDataSourceMetadata(t *testing.T) {
+	d := callerIdentityDataSource{}
+
+	req := datasource.MetadataRequest{
+		ProviderTypeName: "sitecore",
+	}
+	resp := datasource.MetadataResponse{}
+
+	d.Metadata(context.Background(), req, &resp)
+
+	if resp.TypeName != "sitecore_caller_identity" {
+		t.Errorf("Expected TypeName to be 'sitecore_caller_identity', got '%s'", resp.TypeName)
+	}
+}
+
+func TestCallerIdentityDataSourceSchema(t *testing.T) {
+	d := callerIdentityDataSource{}
+
+	req := datasource.SchemaRequest{}
+	resp := datasource.SchemaResponse{}
+
+	d.Schema(context.Background(), req, &resp)
+
+	// Check that the expected attributes are present
+	for _, name := range []string{"organization_id", "client_id", "subject", "audience", "scopes", "permissions", "expires_at", "credential_source"} {
+		if _, ok := resp.Schema.Attributes[name]; !ok {
+			t.Errorf("Expected schema to have %s attribute", name)
+		}
+	}
+
+	// The token itself must never be exposed
+	if _, ok := resp.Schema.Attributes["token"]; ok {
+		t.Error("Expected schema not to have token attribute")
+	}
+}
+
+func TestCallerIdentityDataSourceConfigure(t *testing.T) {
+	d := callerIdentityDataSource{}
+
+	// Test with nil provider data
+	req := datasource.ConfigureRequest{}
+	resp := datasource.ConfigureResponse{}
+
+	d.Configure(context.Background(), req, &resp)
+
+	// Client should remain nil when no provider data is provided
+	if d.client != nil {
+		t.Error("Expected client to remain nil when no provider data is provided")
+	}
+}
@@ -0,0 +1,149 @@
+// Import of automation clients by their ID or OAuth client ID
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// importEnvironmentClient imports an environment client of the given type by its ID or OAuth
+// client ID, or by the client ID of its identity. The project and environment IDs are resolved,
+// so the first plan after the import does not replace the client
+func importEnvironmentClient(ctx context.Context, client *apiclient.Client, clientType apiclient.ClientType, description string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+	var identity environmentClientIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		id = identity.ClientID.ValueString()
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := client.FindEnvironmentClient(id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			"Could not retrieve environment clients: "+errorDetail(err),
+		)
+		return
+	}
+	if found == nil {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			fmt.Sprintf("No environment client with ID or client ID %q exists.", id),
+		)
+		return
+	}
+	if found.ClientType != clientType {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			fmt.Sprintf("Client %q (%s) is not a %s.", found.Name, found.ID, description),
+		)
+		return
+	}
+
+	projectID, environmentID, err := resolveClientEnvironment(client, found)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			"Could not resolve the project and environment of the client: "+errorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), found.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), found.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("description"), optionalString(found.Description))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("client_id"), found.ClientID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment_id"), environmentID)...)
+	addClientSecretNotImportedWarning(description, resp)
+}
+
+// importOrganizationClient imports an organization client by its ID or OAuth client ID, or
+// by the client ID of its identity
+func importOrganizationClient(ctx context.Context, client *apiclient.Client, clientType apiclient.ClientType, description string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+	var identity organizationClientIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		id = identity.ClientID.ValueString()
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := client.FindOrganizationClient(id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			"Could not retrieve organization clients: "+errorDetail(err),
+		)
+		return
+	}
+	if found == nil {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			fmt.Sprintf("No organization client with ID or client ID %q exists.", id),
+		)
+		return
+	}
+	if found.ClientType != clientType {
+		resp.Diagnostics.AddError(
+			"Error importing "+description,
+			fmt.Sprintf("Client %q (%s) is not a %s.", found.Name, found.ID, description),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), found.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), found.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("description"), optionalString(found.Description))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("client_id"), found.ClientID)...)
+	addClientSecretNotImportedWarning(description, resp)
+}
+
+// resolveClientEnvironment returns the project and environment IDs of a client. Older
+// clients only carry the project and environment names, which are looked up instead
+func resolveClientEnvironment(client *apiclient.Client, found *apiclient.ClientDto) (string, string, error) {
+	if found.ProjectID != "" && found.EnvironmentID != "" {
+		return found.ProjectID, found.EnvironmentID, nil
+	}
+
+	project, err := client.FindProject(found.ProjectName)
+	if err != nil {
+		return "", "", err
+	}
+	if project == nil {
+		return "", "", fmt.Errorf("project %q not found", found.ProjectName)
+	}
+
+	environmentID, err := findEnvironmentByName(client, project.ID, found.EnvironmentName)
+	if err != nil {
+		return "", "", err
+	}
+
+	return project.ID, environmentID, nil
+}
+
+// optionalString returns a null string for empty values, as used for optional attributes
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(value)
+}
+
+// addClientSecretNotImportedWarning explains that the secret of an imported client is not in the state
+func addClientSecretNotImportedWarning(description string, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.AddWarning(
+		"Client secret not imported",
+		"The secret of an existing "+description+" cannot be read from the API, so client_secret is not set after the import. "+
+			"To get a new secret, replace the client with 'terraform apply -replace=<address>'.",
+	)
+}
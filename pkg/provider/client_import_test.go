@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// importStateResponse returns an import response with the empty state of the resource
+func importStateResponse(r resource.Resource) resource.ImportStateResponse {
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+
+	return resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+		},
+	}
+}
+
+func TestCMClientResourceImportState(t *testing.T) {
+	client := fixtureClient(t, fixtureRoutes{
+		"/api/clients/v1/environment": `{"items": [
+			{"id": "client", "name": "Deployments", "clientId": "oauth-client", "clientType": 1, "projectName": "XMC", "environmentName": "staging"},
+			{"id": "edge", "name": "Edge", "clientId": "oauth-edge", "clientType": 2, "projectId": "project", "environmentId": "combined"}
+		]}`,
+	})
+
+	t.Run("by client ID", func(t *testing.T) {
+		r := &cmClientResource{client: client}
+		resp := importStateResponse(r)
+
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: "oauth-client"}, &resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+		}
+		if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != "Client secret not imported" {
+			t.Errorf("Expected a warning about the client secret, got %v", resp.Diagnostics)
+		}
+
+		var state cmClientResourceModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+		if state.ID.ValueString() != "client" || state.ClientID.ValueString() != "oauth-client" {
+			t.Errorf("Expected the client IDs in the state, got '%s' and '%s'", state.ID.ValueString(), state.ClientID.ValueString())
+		}
+		if state.ProjectID.ValueString() != "project" || state.EnvironmentID.ValueString() != "combined" {
+			t.Errorf("Expected the resolved project and environment IDs, got '%s' and '%s'", state.ProjectID.ValueString(), state.EnvironmentID.ValueString())
+		}
+		if !state.Description.IsNull() {
+			t.Errorf("Expected no description, got '%s'", state.Description.ValueString())
+		}
+	})
+
+	t.Run("by identity", func(t *testing.T) {
+		r := &cmClientResource{client: client}
+		resp := importStateResponse(r)
+		req := resource.ImportStateRequest{
+			Identity: resourceIdentity(t, r, environmentClientIdentityModel{ClientID: types.StringValue("oauth-client")}),
+		}
+
+		r.ImportState(context.Background(), req, &resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+		}
+
+		var state cmClientResourceModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+		if state.ID.ValueString() != "client" {
+			t.Errorf("Expected ID 'client', got '%s'", state.ID.ValueString())
+		}
+	})
+
+	t.Run("client of another type", func(t *testing.T) {
+		r := &cmClientResource{client: client}
+		resp := importStateResponse(r)
+
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: "edge"}, &resp)
+
+		if !resp.Diagnostics.HasError() {
+			t.Error("Expected an error when importing an Edge client as a CM client")
+		}
+	})
+}
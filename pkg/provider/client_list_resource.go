@@ -0,0 +1,246 @@
+// Automation client list resource implementations
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementations satisfy the expected interfaces
+var (
+	_ list.ListResource              = &environmentClientListResource{}
+	_ list.ListResourceWithConfigure = &environmentClientListResource{}
+	_ list.ListResource              = &organizationClientListResource{}
+	_ list.ListResourceWithConfigure = &organizationClientListResource{}
+)
+
+// NewCMClientListResource lists CM clients
+func NewCMClientListResource() list.ListResource {
+	return &environmentClientListResource{
+		typeName:   "_cm_client",
+		kind:       "CM clients",
+		clientType: apiclient.ClientTypeCM,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &cmClientResource{client: client}
+		},
+	}
+}
+
+// NewEdgeClientListResource lists Edge clients
+func NewEdgeClientListResource() list.ListResource {
+	return &environmentClientListResource{
+		typeName:   "_edge_client",
+		kind:       "Edge clients",
+		clientType: apiclient.ClientTypeEdge,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &edgeClientResource{client: client}
+		},
+	}
+}
+
+// NewEditingHostBuildClientListResource lists Editing Host Build clients
+func NewEditingHostBuildClientListResource() list.ListResource {
+	return &environmentClientListResource{
+		typeName:   "_editing_host_build_client",
+		kind:       "Editing Host Build clients",
+		clientType: apiclient.ClientTypeEditingHost,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &editingHostBuildClientResource{client: client}
+		},
+	}
+}
+
+// NewDeployClientListResource lists Deploy clients
+func NewDeployClientListResource() list.ListResource {
+	return &organizationClientListResource{
+		typeName:   "_deploy_client",
+		kind:       "Deploy clients",
+		clientType: apiclient.ClientTypeDeploy,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &deployClientResource{client: client}
+		},
+	}
+}
+
+// environmentClientListResource is the list resource implementation for environment clients
+type environmentClientListResource struct {
+	client      *apiclient.Client
+	typeName    string
+	kind        string
+	clientType  apiclient.ClientType
+	newResource func(client *apiclient.Client) resource.Resource
+}
+
+// environmentClientListResourceModel maps the list resource schema data
+type environmentClientListResourceModel struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	EnvironmentID types.String `tfsdk:"environment_id"`
+}
+
+// Metadata returns the resource type name
+func (r *environmentClientListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+// ListResourceConfigSchema defines the schema for the list resource
+func (r *environmentClientListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the " + r.kind + " of the environments of the organization",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Description: "Only list the " + r.kind + " of the project with this ID",
+				Optional:    true,
+			},
+			"environment_id": schema.StringAttribute{
+				Description: "Only list the " + r.kind + " of the environment with this ID",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the list resource
+func (r *environmentClientListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	r.client = listResourceClient(req)
+}
+
+// List streams the environment clients of the type, in the requested project or environment
+func (r *environmentClientListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	if listClientMissing(r.client, stream) {
+		return
+	}
+
+	var config environmentClientListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	clients, err := r.client.GetClientsForEnvironment()
+	if err != nil {
+		listErrorResults(stream, "Error listing "+r.kind, "Could not retrieve environment clients: "+errorDetail(err))
+		return
+	}
+
+	var listed []listedResource
+	var skipped diag.Diagnostics
+	for _, found := range clients.Items {
+		if req.Limit > 0 && int64(len(listed)) >= req.Limit {
+			break
+		}
+
+		// Filter on the IDs in the response first, so only matching clients are resolved
+		if found.ClientType != r.clientType || !matchesFilter(found.ProjectID, config.ProjectID) || !matchesFilter(found.EnvironmentID, config.EnvironmentID) {
+			continue
+		}
+
+		projectID, environmentID, err := resolveClientEnvironment(r.client, &found)
+		if err != nil {
+			skipped.AddWarning(
+				"Client not listed",
+				fmt.Sprintf("Could not resolve the project and environment of client %q, so it is not listed: %s", found.Name, errorDetail(err)),
+			)
+			continue
+		}
+		if !config.ProjectID.IsNull() && config.ProjectID.ValueString() != projectID {
+			continue
+		}
+		if !config.EnvironmentID.IsNull() && config.EnvironmentID.ValueString() != environmentID {
+			continue
+		}
+
+		listed = append(listed, listedResource{
+			displayName: found.Name,
+			identity: environmentClientIdentityModel{
+				ProjectID:     types.StringValue(projectID),
+				EnvironmentID: types.StringValue(environmentID),
+				ClientID:      types.StringValue(found.ClientID),
+			},
+			attributes: map[string]attr.Value{
+				"id":             types.StringValue(found.ID),
+				"name":           types.StringValue(found.Name),
+				"description":    optionalString(found.Description),
+				"client_id":      types.StringValue(found.ClientID),
+				"project_id":     types.StringValue(projectID),
+				"environment_id": types.StringValue(environmentID),
+			},
+		})
+	}
+
+	streamListResults(ctx, req, stream, r.newResource(r.client), listed)
+	streamListWarnings(stream, skipped)
+}
+
+// matchesFilter returns whether a value matches an optional filter. Values missing from
+// the response match, as they are only known once the client is resolved
+func matchesFilter(value string, filter types.String) bool {
+	return filter.IsNull() || value == "" || value == filter.ValueString()
+}
+
+// organizationClientListResource is the list resource implementation for organization clients
+type organizationClientListResource struct {
+	client      *apiclient.Client
+	typeName    string
+	kind        string
+	clientType  apiclient.ClientType
+	newResource func(client *apiclient.Client) resource.Resource
+}
+
+// Metadata returns the resource type name
+func (r *organizationClientListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+// ListResourceConfigSchema defines the schema for the list resource
+func (r *organizationClientListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the " + r.kind + " of the organization",
+	}
+}
+
+// Configure adds the provider configured client to the list resource
+func (r *organizationClientListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	r.client = listResourceClient(req)
+}
+
+// List streams the organization clients of the type
+func (r *organizationClientListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	if listClientMissing(r.client, stream) {
+		return
+	}
+
+	clients, err := r.client.GetClientsForOrganization()
+	if err != nil {
+		listErrorResults(stream, "Error listing "+r.kind, "Could not retrieve organization clients: "+errorDetail(err))
+		return
+	}
+
+	var listed []listedResource
+	for _, found := range clients.Items {
+		if found.ClientType != r.clientType {
+			continue
+		}
+
+		listed = append(listed, listedResource{
+			displayName: found.Name,
+			identity:    organizationClientIdentityModel{ClientID: types.StringValue(found.ClientID)},
+			attributes: map[string]attr.Value{
+				"id":          types.StringValue(found.ID),
+				"name":        types.StringValue(found.Name),
+				"description": optionalString(found.Description),
+				"client_id":   types.StringValue(found.ClientID),
+			},
+		})
+	}
+
+	streamListResults(ctx, req, stream, r.newResource(r.client), listed)
+}
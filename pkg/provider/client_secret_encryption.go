@@ -0,0 +1,168 @@
+// Client secret encryption shared by the automation client resources
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// clientSecretEncryptionAttributes returns the schema attributes used to
+// encrypt the client secret instead of storing it in plain text in the state
+func clientSecretEncryptionAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"pgp_key": schema.StringAttribute{
+			Description: "Either a base64 encoded or ASCII armored PGP public key, or an age recipient (age1...), used to encrypt the client secret. " +
+				"When set, client_secret is not stored in the state and encrypted_client_secret is set instead",
+			Optional: true,
+			Validators: []validator.String{
+				clientSecretKeyValidator{},
+			},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"encrypted_client_secret": schema.StringAttribute{
+			Description: "The client secret encrypted with pgp_key, base64 encoded. " +
+				"Decrypt it with 'base64 --decode | gpg --decrypt' or 'base64 --decode | age --decrypt -i <identity file>'",
+			Computed: true,
+		},
+		"key_fingerprint": schema.StringAttribute{
+			Description: "The fingerprint of the PGP key, or the age recipient, used to encrypt the client secret",
+			Computed:    true,
+		},
+	}
+}
+
+// clientSecretValues returns the values to store in the state for a newly created
+// client secret. Without a key the secret is stored as is, otherwise only the
+// encrypted secret and the key fingerprint are stored
+func clientSecretValues(pgpKey types.String, secret string) (clientSecret, encryptedSecret, fingerprint types.String, err error) {
+	if pgpKey.IsNull() || pgpKey.IsUnknown() || strings.TrimSpace(pgpKey.ValueString()) == "" {
+		return types.StringValue(secret), types.StringNull(), types.StringNull(), nil
+	}
+
+	encrypted, keyFingerprint, err := encryptClientSecret(pgpKey.ValueString(), secret)
+	if err != nil {
+		return types.StringNull(), types.StringNull(), types.StringNull(), err
+	}
+
+	return types.StringNull(), types.StringValue(encrypted), types.StringValue(keyFingerprint), nil
+}
+
+// encryptClientSecret encrypts the secret with a PGP public key or an age
+// recipient and returns the base64 encoded ciphertext and the key fingerprint
+func encryptClientSecret(key string, secret string) (string, string, error) {
+	key = strings.TrimSpace(key)
+
+	var ciphertext bytes.Buffer
+	if strings.HasPrefix(key, "age1") {
+		recipient, err := age.ParseX25519Recipient(key)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse age recipient: %v", err)
+		}
+
+		writer, err := age.Encrypt(&ciphertext, recipient)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encrypt client secret: %v", err)
+		}
+		if _, err := writer.Write([]byte(secret)); err != nil {
+			return "", "", fmt.Errorf("failed to encrypt client secret: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			return "", "", fmt.Errorf("failed to encrypt client secret: %v", err)
+		}
+
+		return base64.StdEncoding.EncodeToString(ciphertext.Bytes()), recipient.String(), nil
+	}
+
+	entity, err := readPGPPublicKey(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	writer, err := openpgp.Encrypt(&ciphertext, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt client secret: %v", err)
+	}
+	if _, err := writer.Write([]byte(secret)); err != nil {
+		return "", "", fmt.Errorf("failed to encrypt client secret: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to encrypt client secret: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(ciphertext.Bytes()), hex.EncodeToString(entity.PrimaryKey.Fingerprint), nil
+}
+
+// readPGPPublicKey reads a base64 encoded or ASCII armored PGP public key
+func readPGPPublicKey(key string) (*openpgp.Entity, error) {
+	var entities openpgp.EntityList
+	if strings.HasPrefix(key, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		block, err := armor.Decode(strings.NewReader(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode armored PGP key: %v", err)
+		}
+
+		entities, err = openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PGP key: %v", err)
+		}
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode PGP key, expected a base64 encoded key or an age recipient: %v", err)
+		}
+
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PGP key: %v", err)
+		}
+	}
+
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected exactly one PGP key, found %d", len(entities))
+	}
+
+	return entities[0], nil
+}
+
+// clientSecretKeyValidator checks at plan time that pgp_key can be used for encryption
+type clientSecretKeyValidator struct{}
+
+// Description returns a plain text description of the validator's behavior
+func (v clientSecretKeyValidator) Description(_ context.Context) string {
+	return "value must be a base64 encoded or ASCII armored PGP public key, or an age recipient"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior
+func (v clientSecretKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString validates that the key can be parsed
+func (v clientSecretKeyValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, _, err := encryptClientSecret(req.ConfigValue.ValueString(), ""); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Encryption Key",
+			"The key cannot be used to encrypt the client secret: "+err.Error(),
+		)
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestClientSecretValuesWithoutKey(t *testing.T) {
+	clientSecret, encrypted, fingerprint, err := clientSecretValues(types.StringNull(), "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if clientSecret.ValueString() != "secret" {
+		t.Errorf("Expected client secret to be stored as is, got '%s'", clientSecret.ValueString())
+	}
+	if !encrypted.IsNull() || !fingerprint.IsNull() {
+		t.Error("Expected encrypted secret and fingerprint to be null")
+	}
+}
+
+func TestClientSecretValuesWithPGPKey(t *testing.T) {
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate PGP key: %v", err)
+	}
+
+	var publicKey bytes.Buffer
+	if err := entity.Serialize(&publicKey); err != nil {
+		t.Fatalf("Failed to serialize PGP key: %v", err)
+	}
+
+	var armored bytes.Buffer
+	armorWriter, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("Failed to armor PGP key: %v", err)
+	}
+	armorWriter.Write(publicKey.Bytes())
+	armorWriter.Close()
+
+	keys := map[string]string{
+		"base64":  base64.StdEncoding.EncodeToString(publicKey.Bytes()),
+		"armored": armored.String(),
+	}
+
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			clientSecret, encrypted, fingerprint, err := clientSecretValues(types.StringValue(key), "secret")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !clientSecret.IsNull() {
+				t.Error("Expected client secret to be null when a key is set")
+			}
+			if fingerprint.ValueString() != hex.EncodeToString(entity.PrimaryKey.Fingerprint) {
+				t.Errorf("Expected fingerprint of the key, got '%s'", fingerprint.ValueString())
+			}
+
+			ciphertext, err := base64.StdEncoding.DecodeString(encrypted.ValueString())
+			if err != nil {
+				t.Fatalf("Expected base64 encoded secret: %v", err)
+			}
+
+			message, err := openpgp.ReadMessage(bytes.NewReader(ciphertext), openpgp.EntityList{entity}, nil, nil)
+			if err != nil {
+				t.Fatalf("Failed to decrypt secret: %v", err)
+			}
+			plaintext, err := io.ReadAll(message.UnverifiedBody)
+			if err != nil {
+				t.Fatalf("Failed to read decrypted secret: %v", err)
+			}
+
+			if string(plaintext) != "secret" {
+				t.Errorf("Expected decrypted secret to be 'secret', got '%s'", plaintext)
+			}
+		})
+	}
+}
+
+func TestClientSecretValuesWithAgeRecipient(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("Failed to generate age identity: %v", err)
+	}
+	recipient := identity.Recipient().String()
+
+	clientSecret, encrypted, fingerprint, err := clientSecretValues(types.StringValue(recipient), "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !clientSecret.IsNull() {
+		t.Error("Expected client secret to be null when a key is set")
+	}
+	if fingerprint.ValueString() != recipient {
+		t.Errorf("Expected fingerprint to be the recipient, got '%s'", fingerprint.ValueString())
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted.ValueString())
+	if err != nil {
+		t.Fatalf("Expected base64 encoded secret: %v", err)
+	}
+
+	reader, err := age.Decrypt(bytes.NewReader(ciphertext), identity)
+	if err != nil {
+		t.Fatalf("Failed to decrypt secret: %v", err)
+	}
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read decrypted secret: %v", err)
+	}
+
+	if string(plaintext) != "secret" {
+		t.Errorf("Expected decrypted secret to be 'secret', got '%s'", plaintext)
+	}
+}
+
+func TestClientSecretKeyValidator(t *testing.T) {
+	tests := map[string]struct {
+		key         types.String
+		expectError bool
+	}{
+		"null":            {key: types.StringNull(), expectError: false},
+		"unknown":         {key: types.StringUnknown(), expectError: false},
+		"invalid base64":  {key: types.StringValue("not a key!"), expectError: true},
+		"not a PGP key":   {key: types.StringValue(base64.StdEncoding.EncodeToString([]byte("garbage"))), expectError: true},
+		"invalid age key": {key: types.StringValue("age1invalid"), expectError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("pgp_key"),
+				ConfigValue: test.key,
+			}
+			resp := validator.StringResponse{}
+
+			clientSecretKeyValidator{}.ValidateString(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != test.expectError {
+				t.Errorf("Expected error: %v, got diagnostics: %v", test.expectError, resp.Diagnostics)
+			}
+			if test.expectError && !strings.Contains(resp.Diagnostics[0].Detail(), "cannot be used") {
+				t.Errorf("Unexpected error detail: %s", resp.Diagnostics[0].Detail())
+			}
+		})
+	}
+}
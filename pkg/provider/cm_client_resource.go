@@ -4,7 +4,6 @@ package provider
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,9 +14,12 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &cmClientResource{}
-	_ resource.ResourceWithConfigure   = &cmClientResource{}
-	_ resource.ResourceWithImportState = &cmClientResource{}
+	_ resource.Resource                 = &cmClientResource{}
+	_ resource.ResourceWithConfigure    = &cmClientResource{}
+	_ resource.ResourceWithIdentity     = &cmClientResource{}
+	_ resource.ResourceWithImportState  = &cmClientResource{}
+	_ resource.ResourceWithModifyPlan   = &cmClientResource{}
+	_ resource.ResourceWithUpgradeState = &cmClientResource{}
 )
 
 // NewCMClientResource is a helper function to simplify the provider implementation
@@ -32,23 +34,30 @@ type cmClientResource struct {
 
 // cmClientResourceModel maps the resource schema data
 type cmClientResourceModel struct {
-	ID            types.String `tfsdk:"id"`
-	Name          types.String `tfsdk:"name"`
-	Description   types.String `tfsdk:"description"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	EnvironmentID types.String `tfsdk:"environment_id"`
-	ClientID      types.String `tfsdk:"client_id"`
-	ClientSecret  types.String `tfsdk:"client_secret"`
+	ID                    types.String `tfsdk:"id"`
+	Name                  types.String `tfsdk:"name"`
+	Description           types.String `tfsdk:"description"`
+	ProjectID             types.String `tfsdk:"project_id"`
+	EnvironmentID         types.String `tfsdk:"environment_id"`
+	ClientID              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	PGPKey                types.String `tfsdk:"pgp_key"`
+	EncryptedClientSecret types.String `tfsdk:"encrypted_client_secret"`
+	KeyFingerprint        types.String `tfsdk:"key_fingerprint"`
 }
 
 // Metadata returns the resource type name
 func (r *cmClientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_cm_client"
+
+	// Clients are replaced by updates, which gives them a new client ID
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource
 func (r *cmClientResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Automation Clients ¤ Manages a Sitecore CM automation client",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -86,12 +95,31 @@ func (r *cmClientResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Sensitive:   false,
 			},
 			"client_secret": schema.StringAttribute{
-				Description: "The client secret for authentication",
+				Description: "The client secret for authentication. Not set when pgp_key is set",
 				Computed:    true,
 				Sensitive:   true,
 			},
 		},
 	}
+
+	for name, attribute := range clientSecretEncryptionAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// IdentitySchema defines the identity of the resource
+func (r *cmClientResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = clientIdentitySchema(true)
+}
+
+// ModifyPlan checks that the project exists and that the environment belongs to it
+func (r *cmClientResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the client is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	validateClientEnvironment(ctx, r.client, req, resp)
 }
 
 // Configure adds the provider configured client to the resource
@@ -123,7 +151,7 @@ func (r *cmClientResource) Create(ctx context.Context, req resource.CreateReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating CM client",
-			"Could not create CM client, unexpected error: "+err.Error(),
+			"Could not create CM client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -135,7 +163,7 @@ func (r *cmClientResource) Create(ctx context.Context, req resource.CreateReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving environment clients",
-			"Could not retrieve environment clients to find newly created client: "+err.Error(),
+			"Could not retrieve environment clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -162,7 +190,16 @@ func (r *cmClientResource) Create(ctx context.Context, req resource.CreateReques
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(resourceID) // Using the resource ID from GetClientsForEnvironment
 	plan.ClientID = types.StringValue(clientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the client without its secret so it is tainted and replaced rather than orphaned
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The CM client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -170,6 +207,8 @@ func (r *cmClientResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.EnvironmentID, ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -187,7 +226,7 @@ func (r *cmClientResource) Read(ctx context.Context, req resource.ReadRequest, r
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving environment clients",
-			"Could not retrieve environment clients: "+err.Error(),
+			"Could not retrieve environment clients: "+errorDetail(err),
 		)
 		return
 	}
@@ -202,10 +241,7 @@ func (r *cmClientResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	if foundClient == nil {
-		resp.Diagnostics.AddError(
-			"Client not found",
-			"Could not find CM client with ID: "+state.ID.ValueString(),
-		)
+		removeDeletedResource(ctx, resp, "CM client", state.ID.ValueString())
 		return
 	}
 
@@ -216,6 +252,12 @@ func (r *cmClientResource) Read(ctx context.Context, req resource.ReadRequest, r
 		state.Description = types.StringValue(foundClient.Description)
 	}
 	state.ClientID = types.StringValue(foundClient.ClientID)
+	if foundClient.ProjectID != "" {
+		state.ProjectID = types.StringValue(foundClient.ProjectID)
+	}
+	if foundClient.EnvironmentID != "" {
+		state.EnvironmentID = types.StringValue(foundClient.EnvironmentID)
+	}
 
 	// Note: We can't retrieve the client secret after creation, so we keep the existing value
 
@@ -225,6 +267,8 @@ func (r *cmClientResource) Read(ctx context.Context, req resource.ReadRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: state.ProjectID, EnvironmentID: state.EnvironmentID, ClientID: state.ClientID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -245,7 +289,7 @@ func (r *cmClientResource) Update(ctx context.Context, req resource.UpdateReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating CM client",
-			"Could not delete old CM client: "+err.Error(),
+			"Could not delete old CM client: "+errorDetail(err),
 		)
 		return
 	}
@@ -260,7 +304,7 @@ func (r *cmClientResource) Update(ctx context.Context, req resource.UpdateReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating CM client",
-			"Could not create new CM client: "+err.Error(),
+			"Could not create new CM client: "+errorDetail(err),
 		)
 		return
 	}
@@ -270,7 +314,7 @@ func (r *cmClientResource) Update(ctx context.Context, req resource.UpdateReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving environment clients",
-			"Could not retrieve environment clients to find newly created client: "+err.Error(),
+			"Could not retrieve environment clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -295,7 +339,16 @@ func (r *cmClientResource) Update(ctx context.Context, req resource.UpdateReques
 	// Update state with new values
 	plan.ID = types.StringValue(newResourceID)
 	plan.ClientID = types.StringValue(clientResponse.ClientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the new client ID, as the old client has already been deleted
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The CM client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -303,6 +356,8 @@ func (r *cmClientResource) Update(ctx context.Context, req resource.UpdateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.EnvironmentID, ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -320,7 +375,7 @@ func (r *cmClientResource) Delete(ctx context.Context, req resource.DeleteReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting CM client",
-			"Could not delete CM client, unexpected error: "+err.Error(),
+			"Could not delete CM client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -328,6 +383,13 @@ func (r *cmClientResource) Delete(ctx context.Context, req resource.DeleteReques
 
 // ImportState imports an existing CM client into Terraform state
 func (r *cmClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Expected format: id or client_id
+	importEnvironmentClient(ctx, r.client, apiclient.ClientTypeCM, "CM client", req, resp)
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *cmClientResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
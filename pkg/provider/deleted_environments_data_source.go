@@ -0,0 +1,159 @@
+// Deleted environments data source implementation
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ datasource.DataSource              = &deletedEnvironmentsDataSource{}
+	_ datasource.DataSourceWithConfigure = &deletedEnvironmentsDataSource{}
+)
+
+// NewDeletedEnvironmentsDataSource is a helper function to simplify the provider implementation
+func NewDeletedEnvironmentsDataSource() datasource.DataSource {
+	return &deletedEnvironmentsDataSource{}
+}
+
+// deletedEnvironmentsDataSource is the data source implementation
+type deletedEnvironmentsDataSource struct {
+	client *apiclient.Client
+}
+
+// deletedEnvironmentsDataSourceModel maps the data source schema data
+type deletedEnvironmentsDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	ProjectID    types.String `tfsdk:"project_id"`
+	Environments types.List   `tfsdk:"environments"`
+}
+
+// deletedEnvironmentModelAttributeTypes are the attribute types of an element of the environments list
+var deletedEnvironmentModelAttributeTypes = map[string]attr.Type{
+	"id":         types.StringType,
+	"name":       types.StringType,
+	"type":       types.StringType,
+	"host":       types.StringType,
+	"deleted_at": types.StringType,
+	"deleted_by": types.StringType,
+}
+
+// Metadata returns the data source type name
+func (d *deletedEnvironmentsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deleted_environments"
+}
+
+// Schema defines the schema for the data source
+func (d *deletedEnvironmentsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Environments ¤ Use this data source to list the deleted environments of a project that can still be restored",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source, the ID of the project",
+				Computed:    true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "The ID of the project",
+				Required:    true,
+			},
+			"environments": schema.ListNestedAttribute{
+				Description: "The deleted environments of the project",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "The ID of the environment",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the environment",
+							Computed:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "The type of the environment, 'cm' or 'eh', empty for combined environments",
+							Computed:    true,
+						},
+						"host": schema.StringAttribute{
+							Description: "The host of the environment",
+							Computed:    true,
+						},
+						"deleted_at": schema.StringAttribute{
+							Description: "When the environment was deleted",
+							Computed:    true,
+						},
+						"deleted_by": schema.StringAttribute{
+							Description: "Who deleted the environment",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source
+func (d *deletedEnvironmentsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*apiclient.Client)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (d *deletedEnvironmentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state deletedEnvironmentsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the deleted environments of the project
+	environments, err := d.client.GetDeletedProjectEnvironments(state.ProjectID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading deleted environments",
+			"Could not read deleted environments for project "+state.ProjectID.ValueString()+": "+errorDetail(err),
+		)
+		return
+	}
+
+	// Map the environments to the schema
+	elements := make([]attr.Value, 0, len(environments))
+	for _, environment := range environments {
+		element, diags := types.ObjectValue(deletedEnvironmentModelAttributeTypes, map[string]attr.Value{
+			"id":         types.StringValue(environment.ID),
+			"name":       types.StringValue(environment.Name),
+			"type":       types.StringValue(environment.Type),
+			"host":       types.StringValue(environment.Host),
+			"deleted_at": types.StringValue(environment.DeletedAt),
+			"deleted_by": types.StringValue(environment.DeletedBy),
+		})
+		resp.Diagnostics.Append(diags...)
+		elements = append(elements, element)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = state.ProjectID
+	state.Environments, diags = types.ListValue(types.ObjectType{AttrTypes: deletedEnvironmentModelAttributeTypes}, elements)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
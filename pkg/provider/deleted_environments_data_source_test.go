@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestDeletedEnvironmentsDataSourceMetadata(t *testing.T) {
+	d := deletedEnvironmentsDataSource{}
+
+	req := datasource.MetadataRequest{
+		ProviderTypeName: "sitecore",
+	}
+	resp := datasource.MetadataResponse{}
+
+	d.Metadata(context.Background(), req, &resp)
+
+	if resp.TypeName != "sitecore_deleted_environments" {
+		t.Errorf("Expected TypeName to be 'sitecore_deleted_environments', got '%s'", resp.TypeName)
+	}
+}
+
+func TestDeletedEnvironmentsDataSourceSchema(t *testing.T) {
+	d := deletedEnvironmentsDataSource{}
+
+	req := datasource.SchemaRequest{}
+	resp := datasource.SchemaResponse{}
+
+	d.Schema(context.Background(), req, &resp)
+
+	// Check that the expected attributes are present
+	for _, name := range []string{"id", "project_id", "environments"} {
+		if _, ok := resp.Schema.Attributes[name]; !ok {
+			t.Errorf("Expected schema to have %s attribute", name)
+		}
+	}
+}
+
+func TestDeletedEnvironmentsDataSourceConfigure(t *testing.T) {
+	d := deletedEnvironmentsDataSource{}
+
+	// Test with nil provider data
+	req := datasource.ConfigureRequest{}
+	resp := datasource.ConfigureResponse{}
+
+	d.Configure(context.Background(), req, &resp)
+
+	// Client should remain nil when no provider data is provided
+	if d.client != nil {
+		t.Error("Expected client to remain nil when no provider data is provided")
+	}
+}
@@ -4,7 +4,6 @@ package provider
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,9 +14,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &deployClientResource{}
-	_ resource.ResourceWithConfigure   = &deployClientResource{}
-	_ resource.ResourceWithImportState = &deployClientResource{}
+	_ resource.Resource                 = &deployClientResource{}
+	_ resource.ResourceWithConfigure    = &deployClientResource{}
+	_ resource.ResourceWithIdentity     = &deployClientResource{}
+	_ resource.ResourceWithImportState  = &deployClientResource{}
+	_ resource.ResourceWithUpgradeState = &deployClientResource{}
 )
 
 // NewDeployClientResource is a helper function to simplify the provider implementation
@@ -32,21 +33,28 @@ type deployClientResource struct {
 
 // deployClientResourceModel maps the resource schema data
 type deployClientResourceModel struct {
-	ID           types.String `tfsdk:"id"`
-	Name         types.String `tfsdk:"name"`
-	Description  types.String `tfsdk:"description"`
-	ClientID     types.String `tfsdk:"client_id"`
-	ClientSecret types.String `tfsdk:"client_secret"`
+	ID                    types.String `tfsdk:"id"`
+	Name                  types.String `tfsdk:"name"`
+	Description           types.String `tfsdk:"description"`
+	ClientID              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	PGPKey                types.String `tfsdk:"pgp_key"`
+	EncryptedClientSecret types.String `tfsdk:"encrypted_client_secret"`
+	KeyFingerprint        types.String `tfsdk:"key_fingerprint"`
 }
 
 // Metadata returns the resource type name
 func (r *deployClientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_deploy_client"
+
+	// Clients are replaced by updates, which gives them a new client ID
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource
 func (r *deployClientResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Automation Clients ¤ Manages a Sitecore Deploy automation client",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -76,12 +84,21 @@ func (r *deployClientResource) Schema(_ context.Context, _ resource.SchemaReques
 				Sensitive:   false,
 			},
 			"client_secret": schema.StringAttribute{
-				Description: "The client secret for authentication",
+				Description: "The client secret for authentication. Not set when pgp_key is set",
 				Computed:    true,
 				Sensitive:   true,
 			},
 		},
 	}
+
+	for name, attribute := range clientSecretEncryptionAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// IdentitySchema defines the identity of the resource
+func (r *deployClientResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = clientIdentitySchema(false)
 }
 
 // Configure adds the provider configured client to the resource
@@ -111,7 +128,7 @@ func (r *deployClientResource) Create(ctx context.Context, req resource.CreateRe
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating Deploy client",
-			"Could not create Deploy client, unexpected error: "+err.Error(),
+			"Could not create Deploy client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -123,7 +140,7 @@ func (r *deployClientResource) Create(ctx context.Context, req resource.CreateRe
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving organization clients",
-			"Could not retrieve organization clients to find newly created client: "+err.Error(),
+			"Could not retrieve organization clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -150,7 +167,16 @@ func (r *deployClientResource) Create(ctx context.Context, req resource.CreateRe
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(resourceID) // Using the resource ID from GetClientsForOrganization
 	plan.ClientID = types.StringValue(clientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the client without its secret so it is tainted and replaced rather than orphaned
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The Deploy client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -158,6 +184,8 @@ func (r *deployClientResource) Create(ctx context.Context, req resource.CreateRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, organizationClientIdentityModel{ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -175,7 +203,7 @@ func (r *deployClientResource) Read(ctx context.Context, req resource.ReadReques
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving organization clients",
-			"Could not retrieve organization clients: "+err.Error(),
+			"Could not retrieve organization clients: "+errorDetail(err),
 		)
 		return
 	}
@@ -190,10 +218,7 @@ func (r *deployClientResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	if foundClient == nil {
-		resp.Diagnostics.AddError(
-			"Client not found",
-			"Could not find Deploy client with ID: "+state.ID.ValueString(),
-		)
+		removeDeletedResource(ctx, resp, "Deploy client", state.ID.ValueString())
 		return
 	}
 
@@ -213,6 +238,8 @@ func (r *deployClientResource) Read(ctx context.Context, req resource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, organizationClientIdentityModel{ClientID: state.ClientID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -241,7 +268,7 @@ func (r *deployClientResource) Update(ctx context.Context, req resource.UpdateRe
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Deploy client",
-			"Could not delete old Deploy client: "+err.Error(),
+			"Could not delete old Deploy client: "+errorDetail(err),
 		)
 		return
 	}
@@ -254,7 +281,7 @@ func (r *deployClientResource) Update(ctx context.Context, req resource.UpdateRe
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Deploy client",
-			"Could not create new Deploy client: "+err.Error(),
+			"Could not create new Deploy client: "+errorDetail(err),
 		)
 		return
 	}
@@ -264,7 +291,7 @@ func (r *deployClientResource) Update(ctx context.Context, req resource.UpdateRe
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving organization clients",
-			"Could not retrieve organization clients to find newly created client: "+err.Error(),
+			"Could not retrieve organization clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -289,7 +316,16 @@ func (r *deployClientResource) Update(ctx context.Context, req resource.UpdateRe
 	// Update state with new values
 	plan.ID = types.StringValue(newResourceID)
 	plan.ClientID = types.StringValue(clientResponse.ClientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the new client ID, as the old client has already been deleted
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The Deploy client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -297,6 +333,8 @@ func (r *deployClientResource) Update(ctx context.Context, req resource.UpdateRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, organizationClientIdentityModel{ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -314,7 +352,7 @@ func (r *deployClientResource) Delete(ctx context.Context, req resource.DeleteRe
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Deploy client",
-			"Could not delete Deploy client, unexpected error: "+err.Error(),
+			"Could not delete Deploy client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -322,6 +360,13 @@ func (r *deployClientResource) Delete(ctx context.Context, req resource.DeleteRe
 
 // ImportState imports an existing Deploy client into Terraform state
 func (r *deployClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Expected format: id or client_id
+	importOrganizationClient(ctx, r.client, apiclient.ClientTypeDeploy, "deploy client", req, resp)
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *deployClientResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
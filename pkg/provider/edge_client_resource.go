@@ -4,7 +4,6 @@ package provider
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,9 +14,12 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &edgeClientResource{}
-	_ resource.ResourceWithConfigure   = &edgeClientResource{}
-	_ resource.ResourceWithImportState = &edgeClientResource{}
+	_ resource.Resource                 = &edgeClientResource{}
+	_ resource.ResourceWithConfigure    = &edgeClientResource{}
+	_ resource.ResourceWithIdentity     = &edgeClientResource{}
+	_ resource.ResourceWithImportState  = &edgeClientResource{}
+	_ resource.ResourceWithModifyPlan   = &edgeClientResource{}
+	_ resource.ResourceWithUpgradeState = &edgeClientResource{}
 )
 
 // NewEdgeClientResource is a helper function to simplify the provider implementation
@@ -32,23 +34,30 @@ type edgeClientResource struct {
 
 // edgeClientResourceModel maps the resource schema data
 type edgeClientResourceModel struct {
-	ID            types.String `tfsdk:"id"`
-	Name          types.String `tfsdk:"name"`
-	Description   types.String `tfsdk:"description"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	EnvironmentID types.String `tfsdk:"environment_id"`
-	ClientID      types.String `tfsdk:"client_id"`
-	ClientSecret  types.String `tfsdk:"client_secret"`
+	ID                    types.String `tfsdk:"id"`
+	Name                  types.String `tfsdk:"name"`
+	Description           types.String `tfsdk:"description"`
+	ProjectID             types.String `tfsdk:"project_id"`
+	EnvironmentID         types.String `tfsdk:"environment_id"`
+	ClientID              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	PGPKey                types.String `tfsdk:"pgp_key"`
+	EncryptedClientSecret types.String `tfsdk:"encrypted_client_secret"`
+	KeyFingerprint        types.String `tfsdk:"key_fingerprint"`
 }
 
 // Metadata returns the resource type name
 func (r *edgeClientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_edge_client"
+
+	// Clients are replaced by updates, which gives them a new client ID
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource
 func (r *edgeClientResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Automation Clients ¤ Manages a Sitecore Edge automation client",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -92,12 +101,31 @@ func (r *edgeClientResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Sensitive:   false,
 			},
 			"client_secret": schema.StringAttribute{
-				Description: "The client secret for authentication",
+				Description: "The client secret for authentication. Not set when pgp_key is set",
 				Computed:    true,
 				Sensitive:   true,
 			},
 		},
 	}
+
+	for name, attribute := range clientSecretEncryptionAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// IdentitySchema defines the identity of the resource
+func (r *edgeClientResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = clientIdentitySchema(true)
+}
+
+// ModifyPlan checks that the project exists and that the environment belongs to it
+func (r *edgeClientResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the client is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	validateClientEnvironment(ctx, r.client, req, resp)
 }
 
 // Configure adds the provider configured client to the resource
@@ -129,7 +157,7 @@ func (r *edgeClientResource) Create(ctx context.Context, req resource.CreateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating Edge client",
-			"Could not create Edge client, unexpected error: "+err.Error(),
+			"Could not create Edge client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -141,7 +169,7 @@ func (r *edgeClientResource) Create(ctx context.Context, req resource.CreateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving organization clients",
-			"Could not retrieve organization clients to find newly created client: "+err.Error(),
+			"Could not retrieve organization clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -168,7 +196,16 @@ func (r *edgeClientResource) Create(ctx context.Context, req resource.CreateRequ
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(resourceID) // Using the resource ID from GetClientsForOrganization
 	plan.ClientID = types.StringValue(clientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the client without its secret so it is tainted and replaced rather than orphaned
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The Edge client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -176,6 +213,8 @@ func (r *edgeClientResource) Create(ctx context.Context, req resource.CreateRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.EnvironmentID, ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -193,7 +232,7 @@ func (r *edgeClientResource) Read(ctx context.Context, req resource.ReadRequest,
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving organization clients",
-			"Could not retrieve organization clients: "+err.Error(),
+			"Could not retrieve organization clients: "+errorDetail(err),
 		)
 		return
 	}
@@ -208,10 +247,7 @@ func (r *edgeClientResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	if foundClient == nil {
-		resp.Diagnostics.AddError(
-			"Client not found",
-			"Could not find Edge client with ID: "+state.ID.ValueString(),
-		)
+		removeDeletedResource(ctx, resp, "Edge client", state.ID.ValueString())
 		return
 	}
 
@@ -222,6 +258,12 @@ func (r *edgeClientResource) Read(ctx context.Context, req resource.ReadRequest,
 		state.Description = types.StringValue(foundClient.Description)
 	}
 	state.ClientID = types.StringValue(foundClient.ClientID)
+	if foundClient.ProjectID != "" {
+		state.ProjectID = types.StringValue(foundClient.ProjectID)
+	}
+	if foundClient.EnvironmentID != "" {
+		state.EnvironmentID = types.StringValue(foundClient.EnvironmentID)
+	}
 
 	// Note: We can't retrieve the client secret after creation, so we keep the existing value
 
@@ -231,6 +273,8 @@ func (r *edgeClientResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: state.ProjectID, EnvironmentID: state.EnvironmentID, ClientID: state.ClientID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -259,7 +303,7 @@ func (r *edgeClientResource) Update(ctx context.Context, req resource.UpdateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Edge client",
-			"Could not delete old Edge client: "+err.Error(),
+			"Could not delete old Edge client: "+errorDetail(err),
 		)
 		return
 	}
@@ -274,7 +318,7 @@ func (r *edgeClientResource) Update(ctx context.Context, req resource.UpdateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Edge client",
-			"Could not create new Edge client: "+err.Error(),
+			"Could not create new Edge client: "+errorDetail(err),
 		)
 		return
 	}
@@ -284,7 +328,7 @@ func (r *edgeClientResource) Update(ctx context.Context, req resource.UpdateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving organization clients",
-			"Could not retrieve organization clients to find newly created client: "+err.Error(),
+			"Could not retrieve organization clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -309,7 +353,16 @@ func (r *edgeClientResource) Update(ctx context.Context, req resource.UpdateRequ
 	// Update state with new values
 	plan.ID = types.StringValue(newResourceID)
 	plan.ClientID = types.StringValue(clientResponse.ClientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the new client ID, as the old client has already been deleted
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The Edge client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -317,6 +370,8 @@ func (r *edgeClientResource) Update(ctx context.Context, req resource.UpdateRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.EnvironmentID, ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -334,7 +389,7 @@ func (r *edgeClientResource) Delete(ctx context.Context, req resource.DeleteRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Edge client",
-			"Could not delete Edge client, unexpected error: "+err.Error(),
+			"Could not delete Edge client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -342,6 +397,13 @@ func (r *edgeClientResource) Delete(ctx context.Context, req resource.DeleteRequ
 
 // ImportState imports an existing Edge client into Terraform state
 func (r *edgeClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Expected format: id or client_id
+	importEnvironmentClient(ctx, r.client, apiclient.ClientTypeEdge, "Edge client", req, resp)
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *edgeClientResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
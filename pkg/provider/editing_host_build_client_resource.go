@@ -4,7 +4,6 @@ package provider
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,9 +14,12 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &editingHostBuildClientResource{}
-	_ resource.ResourceWithConfigure   = &editingHostBuildClientResource{}
-	_ resource.ResourceWithImportState = &editingHostBuildClientResource{}
+	_ resource.Resource                 = &editingHostBuildClientResource{}
+	_ resource.ResourceWithConfigure    = &editingHostBuildClientResource{}
+	_ resource.ResourceWithIdentity     = &editingHostBuildClientResource{}
+	_ resource.ResourceWithImportState  = &editingHostBuildClientResource{}
+	_ resource.ResourceWithModifyPlan   = &editingHostBuildClientResource{}
+	_ resource.ResourceWithUpgradeState = &editingHostBuildClientResource{}
 )
 
 // NewEditingHostBuildClientResource is a helper function to simplify the provider implementation
@@ -32,23 +34,30 @@ type editingHostBuildClientResource struct {
 
 // editingHostBuildClientResourceModel maps the resource schema data
 type editingHostBuildClientResourceModel struct {
-	ID            types.String `tfsdk:"id"`
-	Name          types.String `tfsdk:"name"`
-	Description   types.String `tfsdk:"description"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	EnvironmentID types.String `tfsdk:"environment_id"`
-	ClientID      types.String `tfsdk:"client_id"`
-	ClientSecret  types.String `tfsdk:"client_secret"`
+	ID                    types.String `tfsdk:"id"`
+	Name                  types.String `tfsdk:"name"`
+	Description           types.String `tfsdk:"description"`
+	ProjectID             types.String `tfsdk:"project_id"`
+	EnvironmentID         types.String `tfsdk:"environment_id"`
+	ClientID              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	PGPKey                types.String `tfsdk:"pgp_key"`
+	EncryptedClientSecret types.String `tfsdk:"encrypted_client_secret"`
+	KeyFingerprint        types.String `tfsdk:"key_fingerprint"`
 }
 
 // Metadata returns the resource type name
 func (r *editingHostBuildClientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_editing_host_build_client"
+
+	// Clients are replaced by updates, which gives them a new client ID
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource
 func (r *editingHostBuildClientResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Automation Clients ¤ Manages a Sitecore Editing Host Build automation client",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -92,12 +101,31 @@ func (r *editingHostBuildClientResource) Schema(_ context.Context, _ resource.Sc
 				Sensitive:   false,
 			},
 			"client_secret": schema.StringAttribute{
-				Description: "The client secret for authentication",
+				Description: "The client secret for authentication. Not set when pgp_key is set",
 				Computed:    true,
 				Sensitive:   true,
 			},
 		},
 	}
+
+	for name, attribute := range clientSecretEncryptionAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// IdentitySchema defines the identity of the resource
+func (r *editingHostBuildClientResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = clientIdentitySchema(true)
+}
+
+// ModifyPlan checks that the project exists and that the environment belongs to it
+func (r *editingHostBuildClientResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the client is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	validateClientEnvironment(ctx, r.client, req, resp)
 }
 
 // Configure adds the provider configured client to the resource
@@ -129,7 +157,7 @@ func (r *editingHostBuildClientResource) Create(ctx context.Context, req resourc
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating Editing Host Build client",
-			"Could not create Editing Host Build client, unexpected error: "+err.Error(),
+			"Could not create Editing Host Build client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -141,7 +169,7 @@ func (r *editingHostBuildClientResource) Create(ctx context.Context, req resourc
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving environment clients",
-			"Could not retrieve environment clients to find newly created client: "+err.Error(),
+			"Could not retrieve environment clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -168,7 +196,16 @@ func (r *editingHostBuildClientResource) Create(ctx context.Context, req resourc
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(resourceID) // Using the resource ID from GetClientsForEnvironment
 	plan.ClientID = types.StringValue(clientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the client without its secret so it is tainted and replaced rather than orphaned
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The editing host build client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -176,6 +213,8 @@ func (r *editingHostBuildClientResource) Create(ctx context.Context, req resourc
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.EnvironmentID, ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -193,7 +232,7 @@ func (r *editingHostBuildClientResource) Read(ctx context.Context, req resource.
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving environment clients",
-			"Could not retrieve environment clients: "+err.Error(),
+			"Could not retrieve environment clients: "+errorDetail(err),
 		)
 		return
 	}
@@ -208,10 +247,7 @@ func (r *editingHostBuildClientResource) Read(ctx context.Context, req resource.
 	}
 
 	if foundClient == nil {
-		resp.Diagnostics.AddError(
-			"Client not found",
-			"Could not find Editing Host Build client with ID: "+state.ID.ValueString(),
-		)
+		removeDeletedResource(ctx, resp, "Editing host build client", state.ID.ValueString())
 		return
 	}
 
@@ -222,6 +258,12 @@ func (r *editingHostBuildClientResource) Read(ctx context.Context, req resource.
 		state.Description = types.StringValue(foundClient.Description)
 	}
 	state.ClientID = types.StringValue(foundClient.ClientID)
+	if foundClient.ProjectID != "" {
+		state.ProjectID = types.StringValue(foundClient.ProjectID)
+	}
+	if foundClient.EnvironmentID != "" {
+		state.EnvironmentID = types.StringValue(foundClient.EnvironmentID)
+	}
 	// Note: We can't retrieve the client secret after creation, so we keep the existing value
 
 	// Set refreshed state
@@ -230,6 +272,8 @@ func (r *editingHostBuildClientResource) Read(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: state.ProjectID, EnvironmentID: state.EnvironmentID, ClientID: state.ClientID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -258,7 +302,7 @@ func (r *editingHostBuildClientResource) Update(ctx context.Context, req resourc
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Editing Host Build client",
-			"Could not delete old Editing Host Build client: "+err.Error(),
+			"Could not delete old Editing Host Build client: "+errorDetail(err),
 		)
 		return
 	}
@@ -273,7 +317,7 @@ func (r *editingHostBuildClientResource) Update(ctx context.Context, req resourc
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Editing Host Build client",
-			"Could not create new Editing Host Build client: "+err.Error(),
+			"Could not create new Editing Host Build client: "+errorDetail(err),
 		)
 		return
 	}
@@ -283,7 +327,7 @@ func (r *editingHostBuildClientResource) Update(ctx context.Context, req resourc
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving environment clients",
-			"Could not retrieve environment clients to find newly created client: "+err.Error(),
+			"Could not retrieve environment clients to find newly created client: "+errorDetail(err),
 		)
 		return
 	}
@@ -308,7 +352,16 @@ func (r *editingHostBuildClientResource) Update(ctx context.Context, req resourc
 	// Update state with new values
 	plan.ID = types.StringValue(newResourceID)
 	plan.ClientID = types.StringValue(clientResponse.ClientID)
-	plan.ClientSecret = types.StringValue(clientResponse.ClientSecret)
+	plan.ClientSecret, plan.EncryptedClientSecret, plan.KeyFingerprint, err = clientSecretValues(plan.PGPKey, clientResponse.ClientSecret)
+	if err != nil {
+		// Save the new client ID, as the old client has already been deleted
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Error encrypting client secret",
+			"The editing host build client was created, but its secret could not be encrypted: "+errorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -316,6 +369,8 @@ func (r *editingHostBuildClientResource) Update(ctx context.Context, req resourc
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentClientIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.EnvironmentID, ClientID: plan.ClientID}, &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -333,7 +388,7 @@ func (r *editingHostBuildClientResource) Delete(ctx context.Context, req resourc
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Editing Host Build client",
-			"Could not delete Editing Host Build client, unexpected error: "+err.Error(),
+			"Could not delete Editing Host Build client, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -341,6 +396,13 @@ func (r *editingHostBuildClientResource) Delete(ctx context.Context, req resourc
 
 // ImportState imports an existing Editing Host Build client into Terraform state
 func (r *editingHostBuildClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Expected format: id or client_id
+	importEnvironmentClient(ctx, r.client, apiclient.ClientTypeEditingHost, "editing host build client", req, resp)
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *editingHostBuildClientResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
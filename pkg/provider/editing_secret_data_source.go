@@ -77,7 +77,7 @@ func (d *editingSecretDataSource) Read(ctx context.Context, req datasource.ReadR
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading editing secret",
-			"Could not read editing secret for environment "+state.EnvironmentID.ValueString()+": "+err.Error(),
+			"Could not read editing secret for environment "+state.EnvironmentID.ValueString()+": "+errorDetail(err),
 		)
 		return
 	}
@@ -3,21 +3,30 @@ package provider
 
 import (
 	"context"
+	"errors"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &ehEnvironmentResource{}
-	_ resource.ResourceWithConfigure   = &ehEnvironmentResource{}
-	_ resource.ResourceWithImportState = &ehEnvironmentResource{}
+	_ resource.Resource                 = &ehEnvironmentResource{}
+	_ resource.ResourceWithConfigure    = &ehEnvironmentResource{}
+	_ resource.ResourceWithIdentity     = &ehEnvironmentResource{}
+	_ resource.ResourceWithImportState  = &ehEnvironmentResource{}
+	_ resource.ResourceWithMoveState    = &ehEnvironmentResource{}
+	_ resource.ResourceWithModifyPlan   = &ehEnvironmentResource{}
+	_ resource.ResourceWithUpgradeState = &ehEnvironmentResource{}
 )
 
 // NewEHEnvironmentResource is a helper function to simplify the provider implementation
@@ -32,23 +41,32 @@ type ehEnvironmentResource struct {
 
 // ehEnvironmentResourceModel maps the resource schema data
 type ehEnvironmentResourceModel struct {
-	ID                      types.String `tfsdk:"id"`
-	Name                    types.String `tfsdk:"name"`
-	ProjectID               types.String `tfsdk:"project_id"`
-	IsProd                  types.Bool   `tfsdk:"is_prod"`
-	CmEnvironmentId         types.String `tfsdk:"cm_environment_id"`
-	Host                    types.String `tfsdk:"host"`
-	PlatformTenantId        types.String `tfsdk:"platform_tenant_id"`
-	PlatformTenantName      types.String `tfsdk:"platform_tenant_name"`
-	TenantType              types.String `tfsdk:"tenant_type"`
-	CreatedAt               types.String `tfsdk:"created_at"`
-	CreatedBy               types.String `tfsdk:"created_by"`
-	LastUpdatedBy           types.String `tfsdk:"last_updated_by"`
-	LastUpdatedAt           types.String `tfsdk:"last_updated_at"`
-	IsDeleted               types.Bool   `tfsdk:"is_deleted"`
-	PreviewContextId        types.String `tfsdk:"preview_context_id"`
-	LiveContextId           types.String `tfsdk:"live_context_id"`
-	HighAvailabilityEnabled types.Bool   `tfsdk:"high_availability_enabled"`
+	ID                         types.String   `tfsdk:"id"`
+	Name                       types.String   `tfsdk:"name"`
+	ProjectID                  types.String   `tfsdk:"project_id"`
+	IsProd                     types.Bool     `tfsdk:"is_prod"`
+	CmEnvironmentId            types.String   `tfsdk:"cm_environment_id"`
+	Host                       types.String   `tfsdk:"host"`
+	PlatformTenantId           types.String   `tfsdk:"platform_tenant_id"`
+	PlatformTenantName         types.String   `tfsdk:"platform_tenant_name"`
+	TenantType                 types.String   `tfsdk:"tenant_type"`
+	CreatedAt                  types.String   `tfsdk:"created_at"`
+	CreatedBy                  types.String   `tfsdk:"created_by"`
+	LastUpdatedBy              types.String   `tfsdk:"last_updated_by"`
+	LastUpdatedAt              types.String   `tfsdk:"last_updated_at"`
+	IsDeleted                  types.Bool     `tfsdk:"is_deleted"`
+	RestoreIfDeleted           types.Bool     `tfsdk:"restore_if_deleted"`
+	AdoptExisting              types.Bool     `tfsdk:"adopt_existing"`
+	PreviewContextId           types.String   `tfsdk:"preview_context_id"`
+	LiveContextId              types.String   `tfsdk:"live_context_id"`
+	HighAvailabilityEnabled    types.Bool     `tfsdk:"high_availability_enabled"`
+	ProvisioningStatus         types.String   `tfsdk:"provisioning_status"`
+	ProvisioningFailureMessage types.String   `tfsdk:"provisioning_failure_message"`
+	RepositoryBranch           types.String   `tfsdk:"repository_branch"`
+	DeployOnCommit             types.Bool     `tfsdk:"deploy_on_commit"`
+	SitecoreMajorVersion       types.Int64    `tfsdk:"sitecore_major_version"`
+	Region                     types.String   `tfsdk:"region"`
+	Timeouts                   timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name
@@ -57,8 +75,9 @@ func (r *ehEnvironmentResource) Metadata(_ context.Context, req resource.Metadat
 }
 
 // Schema defines the schema for the resource
-func (r *ehEnvironmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ehEnvironmentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Environments ¤ Manages a Sitecore EH-only environment",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -80,12 +99,18 @@ func (r *ehEnvironmentResource) Schema(_ context.Context, _ resource.SchemaReque
 				},
 			},
 			"is_prod": schema.BoolAttribute{
-				Description: "Whether this is a production environment",
+				Description: "Whether this is a production environment. Changing it replaces the environment",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					isProdRequiresReplace(),
+				},
 			},
 			"cm_environment_id": schema.StringAttribute{
-				Description: "The ID of the CM environment to associate with this EH environment",
+				Description: "The ID of the CM environment to associate with this EH environment. Changing it replaces the environment",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"host": schema.StringAttribute{
 				Description: "The host of the environment",
@@ -123,6 +148,11 @@ func (r *ehEnvironmentResource) Schema(_ context.Context, _ resource.SchemaReque
 				Description: "Whether the environment is deleted",
 				Computed:    true,
 			},
+			"restore_if_deleted": schema.BoolAttribute{
+				Description: "Whether to restore the environment when it was deleted outside Terraform, instead of creating a new one. Defaults to false",
+				Optional:    true,
+			},
+			"adopt_existing": adoptExistingAttribute("environment", "in the project"),
 			"preview_context_id": schema.StringAttribute{
 				Description: "The preview context ID",
 				Computed:    true,
@@ -135,10 +165,88 @@ func (r *ehEnvironmentResource) Schema(_ context.Context, _ resource.SchemaReque
 				Description: "Whether high availability is enabled",
 				Computed:    true,
 			},
+			"provisioning_status": schema.StringAttribute{
+				Description: "The provisioning status of the environment, can have the values 'not_started', 'in_progress', 'complete', 'failed' or 'unknown'",
+				Computed:    true,
+			},
+			"provisioning_failure_message": schema.StringAttribute{
+				Description: "The reason the last provisioning of the environment failed",
+				Computed:    true,
+			},
+			"repository_branch": schema.StringAttribute{
+				Description: "The branch of the source code repository that is deployed to the environment",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deploy_on_commit": schema.BoolAttribute{
+				Description: "Whether the environment is deployed automatically when commits are pushed to the repository branch",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sitecore_major_version": schema.Int64Attribute{
+				Description: "The major version of Sitecore running in the environment",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the environment is hosted in, see the sitecoreai_regions data source. Defaults to the region of the organization, changing it forces a new environment",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// IdentitySchema defines the identity of the resource
+func (r *ehEnvironmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = environmentIdentitySchema()
+}
+
+// ModifyPlan checks that the project exists, that the name is not used by another
+// environment in the project, that the editing host is linked to a CM environment in
+// the same project and that a new region is available to the organization
+func (r *ehEnvironmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the environment is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plannedRegion, currentRegion types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("region"), &plannedRegion)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("region"), &currentRegion)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateRegion(r.client, plannedRegion, currentRegion, &resp.Diagnostics)
+	validateEnvironmentPlan(ctx, r.client, req, resp)
+	validateCMEnvironmentLink(ctx, r.client, req, resp)
+	modifyRestorePlan(ctx, req, resp)
+}
+
 // Configure adds the provider configured client to the resource
 func (r *ehEnvironmentResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -158,6 +266,15 @@ func (r *ehEnvironmentResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, r.client.Timeouts.Create)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Parse is_prod parameter (default: false)
 	isProd := false
 	if !plan.IsProd.IsNull() && !plan.IsProd.IsUnknown() {
@@ -170,54 +287,65 @@ func (r *ehEnvironmentResource) Create(ctx context.Context, req resource.CreateR
 		cmEnvironmentId = plan.CmEnvironmentId.ValueString()
 	}
 
-	// Call API with EH environment type
-	createdEnvironment, err := r.client.CreateEnvironment(
-		plan.ProjectID.ValueString(),
-		plan.Name.ValueString(),
-		isProd,
-		apiclient.EnvironmentTypeEhOnly,
-		cmEnvironmentId,
-	)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating EH environment",
-			"Could not create EH environment, unexpected error: "+err.Error(),
-		)
-		return
+	// Parse the optional environment settings, the API applies its defaults to the rest
+	settings := apiclient.EnvironmentSettings{}
+	if !plan.RepositoryBranch.IsNull() && !plan.RepositoryBranch.IsUnknown() {
+		settings.RepositoryBranch = plan.RepositoryBranch.ValueString()
+	}
+	if !plan.DeployOnCommit.IsNull() && !plan.DeployOnCommit.IsUnknown() {
+		settings.DeployOnCommit = plan.DeployOnCommit.ValueBool()
+	}
+	if !plan.SitecoreMajorVersion.IsNull() && !plan.SitecoreMajorVersion.IsUnknown() {
+		settings.SitecoreMajorVersion = int(plan.SitecoreMajorVersion.ValueInt64())
+	}
+	if !plan.Region.IsNull() && !plan.Region.IsUnknown() {
+		settings.Zone = plan.Region.ValueString()
 	}
 
-	// Wait for environment to be ready with context IDs (timeout after 30 minutes)
-	readyEnvironment, err := r.client.WaitForEnvironmentReady(
-		createdEnvironment.ID,
-		10, // 10 minutes timeout
-	)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error waiting for EH environment to be ready",
-			"Could not wait for EH environment to be ready: "+err.Error(),
+	// Take over an existing EH environment with the same name when requested
+	var createdEnvironment *apiclient.Environment
+	var err error
+	if plan.AdoptExisting.ValueBool() {
+		changes := adoptedEnvironmentChanges(plan.RepositoryBranch, plan.DeployOnCommit, plan.SitecoreMajorVersion)
+		createdEnvironment, err = adoptEnvironment(ctx, r.client, plan.ProjectID.ValueString(), plan.Name.ValueString(), apiclient.EnvironmentTypeEhOnly, cmEnvironmentId, plan.IsProd, plan.Region, changes)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error adopting EH environment",
+				"Could not take over the existing EH environment: "+errorDetail(err),
+			)
+			return
+		}
+	}
+
+	if createdEnvironment == nil {
+		// Call API with EH environment type
+		createdEnvironment, err = r.client.CreateEnvironment(
+			ctx,
+			plan.ProjectID.ValueString(),
+			plan.Name.ValueString(),
+			isProd,
+			apiclient.EnvironmentTypeEhOnly,
+			cmEnvironmentId,
+			settings,
 		)
-		return
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating EH environment",
+				"Could not create EH environment, unexpected error: "+errorDetail(err),
+			)
+			return
+		}
 	}
 
-	// Use the ready environment instead of the initially created one
-	createdEnvironment = readyEnvironment
+	// Wait for environment to be ready with context IDs until the create timeout
+	readyEnvironment, waitErr := r.client.WaitForEnvironmentReady(ctx, createdEnvironment.ID)
+	if readyEnvironment != nil {
+		// Use the latest environment instead of the initially created one
+		createdEnvironment = readyEnvironment
+	}
 
 	// Map response body to schema and populate Computed attribute values
-	plan.ID = types.StringValue(createdEnvironment.ID)
-	plan.Name = types.StringValue(createdEnvironment.Name)
-	plan.ProjectID = types.StringValue(createdEnvironment.ProjectID)
-	plan.Host = types.StringValue(createdEnvironment.Host)
-	plan.PlatformTenantId = types.StringValue(createdEnvironment.PlatformTenantId)
-	plan.PlatformTenantName = types.StringValue(createdEnvironment.PlatformTenantName)
-	plan.TenantType = types.StringValue(createdEnvironment.TenantType)
-	plan.CreatedAt = types.StringValue(createdEnvironment.CreatedAt)
-	plan.CreatedBy = types.StringValue(createdEnvironment.CreatedBy)
-	plan.LastUpdatedBy = types.StringValue(createdEnvironment.LastUpdatedBy)
-	plan.LastUpdatedAt = types.StringValue(createdEnvironment.LastUpdatedAt)
-	plan.IsDeleted = types.BoolValue(createdEnvironment.IsDeleted)
-	plan.HighAvailabilityEnabled = types.BoolValue(createdEnvironment.HighAvailabilityEnabled)
-	plan.PreviewContextId = types.StringNull()
-	plan.LiveContextId = types.StringNull()
+	setEHEnvironmentModel(createdEnvironment, &plan)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -225,6 +353,26 @@ func (r *ehEnvironmentResource) Create(ctx context.Context, req resource.CreateR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.ID}, &resp.Diagnostics)
+
+	// The environment is saved even when it is not ready in time, so it is
+	// tainted and replaced on the next apply instead of being left untracked
+	var provisioningErr *apiclient.ProvisioningFailedError
+	if errors.As(waitErr, &provisioningErr) {
+		resp.Diagnostics.AddError(
+			"EH environment provisioning failed",
+			"The EH environment was created but could not be provisioned. It has been marked as tainted and will be replaced on the next apply: "+provisioningErr.Error(),
+		)
+		return
+	}
+	if waitErr != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for EH environment to be ready",
+			"Could not wait for EH environment to be ready: "+errorDetail(waitErr),
+		)
+		return
+	}
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -237,38 +385,71 @@ func (r *ehEnvironmentResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, r.client.Timeouts.Read)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get environment from API
-	environment, err := r.client.GetEnvironment(state.ID.ValueString())
+	environment, err := r.client.GetEnvironment(ctx, state.ID.ValueString())
+	if apiclient.IsNotFoundError(err) {
+		removeDeletedResource(ctx, resp, "EH environment", state.ID.ValueString())
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading EH environment",
-			"Could not read EH environment ID "+state.ID.ValueString()+": "+err.Error(),
+			"Could not read EH environment ID "+state.ID.ValueString()+": "+errorDetail(err),
 		)
 		return
 	}
 
-	// Handle environment not found (soft delete scenario)
-	if environment == nil {
-		resp.State.RemoveResource(ctx)
+	// Soft deleted environments are gone as far as Terraform is concerned,
+	// unless they are restored on the next apply
+	if environment.IsDeleted && !state.RestoreIfDeleted.ValueBool() {
+		removeDeletedResource(ctx, resp, "EH environment", state.ID.ValueString())
 		return
 	}
+	if environment.IsDeleted {
+		tflog.Warn(ctx, "EH environment was deleted outside Terraform, it will be restored on the next apply", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+	}
+
+	// Not every response includes the link, so a missing one keeps the current value.
+	// There is none after an import, so the link is looked up in the project instead
+	cmEnvironmentID := environment.EditingHostEnvironmentDetails.CmEnvironmentId
+	if cmEnvironmentID == "" && state.CmEnvironmentId.IsNull() {
+		cmEnvironmentID, err = r.client.GetLinkedCMEnvironmentID(environment)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading EH environment",
+				"Could not read the CM environment linked to EH environment ID "+state.ID.ValueString()+": "+errorDetail(err),
+			)
+			return
+		}
+
+		// Only imports have no project yet, an EH environment created without a link keeps none
+		if cmEnvironmentID == "" && state.ProjectID.IsNull() {
+			resp.Diagnostics.AddError(
+				"Error importing EH environment",
+				"Could not determine the CM environment linked to EH environment ID "+state.ID.ValueString()+
+					", the API did not return cm_environment_id for it in the environment or in the environments of project "+environment.ProjectID+". "+
+					"Only EH environments linked to a CM environment can be imported.",
+			)
+			return
+		}
+	}
 
 	// Overwrite items with refreshed state
-	state.Name = types.StringValue(environment.Name)
-	state.ProjectID = types.StringValue(environment.ProjectID)
-	state.CmEnvironmentId = types.StringValue(environment.EditingHostEnvironmentDetails.CmEnvironmentId)
-	state.Host = types.StringValue(environment.Host)
-	state.PlatformTenantId = types.StringValue(environment.PlatformTenantId)
-	state.PlatformTenantName = types.StringValue(environment.PlatformTenantName)
-	state.TenantType = types.StringValue(environment.TenantType)
-	state.CreatedAt = types.StringValue(environment.CreatedAt)
-	state.CreatedBy = types.StringValue(environment.CreatedBy)
-	state.LastUpdatedBy = types.StringValue(environment.LastUpdatedBy)
-	state.LastUpdatedAt = types.StringValue(environment.LastUpdatedAt)
-	state.IsDeleted = types.BoolValue(environment.IsDeleted)
-	state.HighAvailabilityEnabled = types.BoolValue(environment.HighAvailabilityEnabled)
-	state.PreviewContextId = types.StringNull()
-	state.LiveContextId = types.StringNull()
+	setEHEnvironmentModel(environment, &state)
+	if cmEnvironmentID != "" {
+		state.CmEnvironmentId = types.StringValue(cmEnvironmentID)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -276,6 +457,8 @@ func (r *ehEnvironmentResource) Read(ctx context.Context, req resource.ReadReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentIdentityModel{ProjectID: state.ProjectID, EnvironmentID: state.ID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -288,6 +471,15 @@ func (r *ehEnvironmentResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, r.client.Timeouts.Update)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Get current state
 	var state ehEnvironmentResourceModel
 	diags = req.State.Get(ctx, &state)
@@ -296,46 +488,46 @@ func (r *ehEnvironmentResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	// Create the environment update
-	environment := apiclient.Environment{
-		ID:        plan.ID.ValueString(),
-		Name:      plan.Name.ValueString(),
-		ProjectID: plan.ProjectID.ValueString(),
+	// Restore the environment first when it was deleted outside Terraform
+	if state.IsDeleted.ValueBool() && plan.RestoreIfDeleted.ValueBool() {
+		_, err := r.client.RestoreEnvironment(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error restoring EH environment",
+				"Could not restore EH environment: "+errorDetail(err),
+			)
+			return
+		}
 	}
 
-	err := r.client.UpdateEnvironment(plan.ProjectID.ValueString(), plan.ID.ValueString(), environment)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating EH environment",
-			"Could not update EH environment, unexpected error: "+err.Error(),
-		)
-		return
+	// Only send the attributes the plan changes, the others keep their current values.
+	// Nothing is sent when the plan only changes attributes Terraform keeps to itself, such as timeouts
+	changes := apiclient.UpdateEnvironmentRequest{}
+	if !plan.Name.Equal(state.Name) {
+		changes.Name = plan.Name.ValueStringPointer()
+	}
+	if !plan.RepositoryBranch.IsUnknown() && !plan.RepositoryBranch.Equal(state.RepositoryBranch) {
+		changes.RepositoryBranch = plan.RepositoryBranch.ValueStringPointer()
+	}
+	if !plan.DeployOnCommit.IsUnknown() && !plan.DeployOnCommit.Equal(state.DeployOnCommit) {
+		changes.DeployOnCommit = plan.DeployOnCommit.ValueBoolPointer()
+	}
+	if !plan.SitecoreMajorVersion.IsUnknown() && !plan.SitecoreMajorVersion.Equal(state.SitecoreMajorVersion) {
+		sitecoreMajorVersion := int(plan.SitecoreMajorVersion.ValueInt64())
+		changes.SitecoreMajorVersion = &sitecoreMajorVersion
 	}
 
-	// Fetch updated environment from API
-	updatedEnvironment, err := r.client.GetEnvironment(plan.ID.ValueString())
+	updatedEnvironment, err := r.client.PatchEnvironment(ctx, plan.ID.ValueString(), changes)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error reading updated EH environment",
-			"Could not read updated EH environment ID "+plan.ID.ValueString()+": "+err.Error(),
+			"Error updating EH environment",
+			"Could not update EH environment, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
 
 	// Update state with refreshed values
-	plan.Name = types.StringValue(updatedEnvironment.Name)
-	plan.Host = types.StringValue(updatedEnvironment.Host)
-	plan.PlatformTenantId = types.StringValue(updatedEnvironment.PlatformTenantId)
-	plan.PlatformTenantName = types.StringValue(updatedEnvironment.PlatformTenantName)
-	plan.TenantType = types.StringValue(updatedEnvironment.TenantType)
-	plan.CreatedAt = types.StringValue(updatedEnvironment.CreatedAt)
-	plan.CreatedBy = types.StringValue(updatedEnvironment.CreatedBy)
-	plan.LastUpdatedBy = types.StringValue(updatedEnvironment.LastUpdatedBy)
-	plan.LastUpdatedAt = types.StringValue(updatedEnvironment.LastUpdatedAt)
-	plan.IsDeleted = types.BoolValue(updatedEnvironment.IsDeleted)
-	plan.HighAvailabilityEnabled = types.BoolValue(updatedEnvironment.HighAvailabilityEnabled)
-	plan.PreviewContextId = types.StringNull()
-	plan.LiveContextId = types.StringNull()
+	setEHEnvironmentModel(updatedEnvironment, &plan)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -343,6 +535,41 @@ func (r *ehEnvironmentResource) Update(ctx context.Context, req resource.UpdateR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.ID}, &resp.Diagnostics)
+}
+
+// setEHEnvironmentModel maps an environment returned by the API to the EH environment resource model
+func setEHEnvironmentModel(environment *apiclient.Environment, model *ehEnvironmentResourceModel) {
+	model.ID = types.StringValue(environment.ID)
+	model.Name = types.StringValue(environment.Name)
+	model.ProjectID = types.StringValue(environment.ProjectID)
+	// Not every response includes the link, a missing one keeps the current value
+	if environment.EditingHostEnvironmentDetails.CmEnvironmentId != "" {
+		model.CmEnvironmentId = types.StringValue(environment.EditingHostEnvironmentDetails.CmEnvironmentId)
+	}
+	model.Host = types.StringValue(environment.Host)
+	model.PlatformTenantId = types.StringValue(environment.PlatformTenantId)
+	model.PlatformTenantName = types.StringValue(environment.PlatformTenantName)
+	model.TenantType = types.StringValue(environment.TenantType)
+	model.IsProd = isProdValue(model.IsProd, environment.TenantType)
+	model.CreatedAt = types.StringValue(environment.CreatedAt)
+	model.CreatedBy = types.StringValue(environment.CreatedBy)
+	model.LastUpdatedBy = types.StringValue(environment.LastUpdatedBy)
+	model.LastUpdatedAt = types.StringValue(environment.LastUpdatedAt)
+	model.IsDeleted = types.BoolValue(environment.IsDeleted)
+	model.HighAvailabilityEnabled = types.BoolValue(environment.HighAvailabilityEnabled)
+	model.ProvisioningStatus = types.StringValue(environment.ProvisioningStatus.String())
+	model.ProvisioningFailureMessage = types.StringValue(environment.ProvisioningLastFailureMessage)
+	model.RepositoryBranch = types.StringValue(environment.RepositoryBranch)
+	model.DeployOnCommit = types.BoolValue(environment.DeployOnCommit)
+	model.SitecoreMajorVersion = types.Int64Value(int64(environment.SitecoreMajorVersion))
+	// Not every response includes the region, a missing one keeps the configured value
+	if environment.Zone != "" || model.Region.IsUnknown() {
+		model.Region = types.StringValue(environment.Zone)
+	}
+	model.PreviewContextId = types.StringNull()
+	model.LiveContextId = types.StringNull()
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -355,12 +582,31 @@ func (r *ehEnvironmentResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, r.client.Timeouts.Delete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Delete the environment
-	err := r.client.DeleteEnvironment(state.ID.ValueString())
+	err := r.client.DeleteEnvironment(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting EH environment",
-			"Could not delete EH environment, unexpected error: "+err.Error(),
+			"Could not delete EH environment, unexpected error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Wait until the environment is gone, so it can be recreated with the same name in the same apply
+	err = r.client.WaitForEnvironmentDeleted(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for EH environment to be deleted",
+			"Could not wait for EH environment to be deleted: "+errorDetail(err),
 		)
 		return
 	}
@@ -368,7 +614,39 @@ func (r *ehEnvironmentResource) Delete(ctx context.Context, req resource.DeleteR
 
 // ImportState imports an existing EH environment into Terraform state
 func (r *ehEnvironmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	// Expected format: environment_id
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Take the environment ID from the identity when imported by identity
+	var identity environmentIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.EnvironmentID)...)
+		}
+		return
+	}
+
+	// Resolve the import ID and save it to the id attribute
+	// Expected format: environment_id or project/environment, where the project is a name or ID
+	id, err := resolveEnvironmentImportID(r.client, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing EH environment",
+			"Could not resolve import ID: "+errorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// MoveState moves the state of a combined environment to this resource with a moved block
+func (r *ehEnvironmentResource) MoveState(_ context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		environmentStateMover(),
+	}
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *ehEnvironmentResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
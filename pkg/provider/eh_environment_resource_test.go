@@ -2,9 +2,18 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 func TestEHEnvironmentResourceMetadata(t *testing.T) {
@@ -40,6 +49,10 @@ func TestEHEnvironmentResourceSchema(t *testing.T) {
 		t.Error("Expected schema to have id attribute")
 	}
 
+	if _, ok := resp.Schema.Blocks["timeouts"]; !ok {
+		t.Error("Expected schema to have timeouts block")
+	}
+
 	if _, ok := resp.Schema.Attributes["name"]; !ok {
 		t.Error("Expected schema to have name attribute")
 	}
@@ -75,3 +88,68 @@ func TestEHEnvironmentResourceConfigure(t *testing.T) {
 		t.Error("Expected client to remain nil when no provider data is provided")
 	}
 }
+
+func TestEHEnvironmentResourceReadAfterImport(t *testing.T) {
+	tests := map[string]struct {
+		listing     string
+		expectError bool
+	}{
+		"link from the project environments": {
+			listing: `[{"id": "test-eh-environment-id", "type": "eh", "editingHostEnvironmentDetails": {"cmEnvironmentId": "test-cm-environment-id"}}]`,
+		},
+		"no link": {
+			listing:     `[{"id": "test-eh-environment-id", "type": "eh"}]`,
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/environments/v2/test-eh-environment-id":
+					// The single environment response does not include the link
+					_, _ = fmt.Fprint(w, `{"id": "test-eh-environment-id", "name": "eh", "projectId": "test-project-id", "type": "eh"}`)
+				case "/api/projects/v2/test-project-id/environments":
+					_, _ = fmt.Fprint(w, test.listing)
+				default:
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			r := &ehEnvironmentResource{client: &apiclient.Client{
+				BaseURL:    server.URL,
+				HTTPClient: server.Client(),
+				Token:      "test-token",
+				Timeouts:   apiclient.DefaultTimeouts,
+			}}
+
+			// An import only sets the ID
+			config := resourceConfig(t, r, map[string]tftypes.Value{
+				"id": tftypes.NewValue(tftypes.String, "test-eh-environment-id"),
+			})
+			state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+			resp := resource.ReadResponse{State: state}
+
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+			if test.expectError {
+				if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "cm_environment_id") {
+					t.Fatalf("Expected an import error naming cm_environment_id, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+			}
+
+			var cmEnvironmentID types.String
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("cm_environment_id"), &cmEnvironmentID)...)
+			if cmEnvironmentID.ValueString() != "test-cm-environment-id" {
+				t.Errorf("Expected cm_environment_id test-cm-environment-id, got %v", cmEnvironmentID)
+			}
+		})
+	}
+}
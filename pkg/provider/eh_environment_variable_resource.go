@@ -12,9 +12,12 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &ehEnvironmentVariableResource{}
-	_ resource.ResourceWithConfigure   = &ehEnvironmentVariableResource{}
-	_ resource.ResourceWithImportState = &ehEnvironmentVariableResource{}
+	_ resource.Resource                 = &ehEnvironmentVariableResource{}
+	_ resource.ResourceWithConfigure    = &ehEnvironmentVariableResource{}
+	_ resource.ResourceWithIdentity     = &ehEnvironmentVariableResource{}
+	_ resource.ResourceWithImportState  = &ehEnvironmentVariableResource{}
+	_ resource.ResourceWithMoveState    = &ehEnvironmentVariableResource{}
+	_ resource.ResourceWithUpgradeState = &ehEnvironmentVariableResource{}
 )
 
 // ehEnvironmentVariableResourceModel maps the resource schema data.
@@ -35,6 +38,9 @@ func NewEHEnvironmentVariableResource() resource.Resource {
 // Metadata returns the resource type name.
 func (r *ehEnvironmentVariableResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_eh_environment_variable"
+
+	// Variables are renamed and moved to other environments in place.
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource.
@@ -47,6 +53,11 @@ func (r *ehEnvironmentVariableResource) Schema(ctx context.Context, req resource
 	resp.Schema.Description = "Environments ¤ Manages an environment variable for a SitecoreAI editing host environment."
 }
 
+// IdentitySchema defines the identity of the resource.
+func (r *ehEnvironmentVariableResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = variableIdentitySchema(false)
+}
+
 // Configure adds the provider-configured client to the resource.
 func (r *ehEnvironmentVariableResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	r.base.Configure(ctx, req, resp)
@@ -82,8 +93,8 @@ func (r *ehEnvironmentVariableResource) Update(ctx context.Context, req resource
 
 // Read refreshes the Terraform state with the latest data.
 func (r *ehEnvironmentVariableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Delegate to the base Read method
-	r.base.Read(ctx, req, resp)
+	// Delegate to the base Read method with target="EH"
+	r.base.Read(ctx, req, resp, "EH")
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -94,18 +105,43 @@ func (r *ehEnvironmentVariableResource) Delete(ctx context.Context, req resource
 
 // ImportState imports an existing environment variable into Terraform state.
 func (r *ehEnvironmentVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Expected format: environment_id:name
-	idParts := strings.Split(req.ID, ":")
-	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid import ID format",
-			"Expected format: environment_id:name",
-		)
-		return
-	}
+	// Expected format: environment_id:name or project/environment/name, or the identity
+	var environmentID, variableName string
+	var identity variableIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		environmentID = identity.EnvironmentID.ValueString()
+		variableName = identity.Name.ValueString()
+	} else if strings.Contains(req.ID, "/") {
+		var target string
+		var err error
+		environmentID, variableName, target, err = resolveVariableImportID(r.base.client, req.ID)
+		if err == nil && target != "" {
+			err = fmt.Errorf("EH environment variables have no target, got %q", target)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid import ID",
+				"Could not resolve import ID: "+errorDetail(err),
+			)
+			return
+		}
+	} else {
+		idParts := strings.Split(req.ID, ":")
+		if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid import ID format",
+				"Expected format: environment_id:name or project/environment/name",
+			)
+			return
+		}
 
-	environmentID := idParts[0]
-	variableName := idParts[1]
+		environmentID = idParts[0]
+		variableName = idParts[1]
+	}
 
 	// Generate composite ID: environment_id:name
 	compositeID := fmt.Sprintf("%s:%s", environmentID, variableName)
@@ -121,7 +157,7 @@ func (r *ehEnvironmentVariableResource) ImportState(ctx context.Context, req res
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading environment variables during import",
-			"Could not read environment variables: "+err.Error(),
+			"Could not read environment variables: "+errorDetail(err),
 		)
 		return
 	}
@@ -156,3 +192,17 @@ func (r *ehEnvironmentVariableResource) ImportState(ctx context.Context, req res
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
+
+// MoveState moves the state of an environment variable with target EH to this resource with a moved block.
+func (r *ehEnvironmentVariableResource) MoveState(_ context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		environmentVariableStateMover("EH", "sitecoreai_eh_environment_variable"),
+	}
+}
+
+// UpgradeState upgrades state written with earlier schema versions.
+func (r *ehEnvironmentVariableResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(migrateVariableID(false)),
+	}
+}
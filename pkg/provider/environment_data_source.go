@@ -29,21 +29,28 @@ type environmentDataSource struct {
 
 // environmentDataSourceModel maps the data source schema data
 type environmentDataSourceModel struct {
-	ID                      types.String `tfsdk:"id"`
-	Name                    types.String `tfsdk:"name"`
-	ProjectID               types.String `tfsdk:"project_id"`
-	Host                    types.String `tfsdk:"host"`
-	PlatformTenantId        types.String `tfsdk:"platform_tenant_id"`
-	PlatformTenantName      types.String `tfsdk:"platform_tenant_name"`
-	TenantType              types.String `tfsdk:"tenant_type"`
-	CreatedAt               types.String `tfsdk:"created_at"`
-	CreatedBy               types.String `tfsdk:"created_by"`
-	LastUpdatedBy           types.String `tfsdk:"last_updated_by"`
-	LastUpdatedAt           types.String `tfsdk:"last_updated_at"`
-	IsDeleted               types.Bool   `tfsdk:"is_deleted"`
-	PreviewContextId        types.String `tfsdk:"preview_context_id"`
-	LiveContextId           types.String `tfsdk:"live_context_id"`
-	HighAvailabilityEnabled types.Bool   `tfsdk:"high_availability_enabled"`
+	ID                         types.String `tfsdk:"id"`
+	Name                       types.String `tfsdk:"name"`
+	ProjectID                  types.String `tfsdk:"project_id"`
+	Host                       types.String `tfsdk:"host"`
+	PlatformTenantId           types.String `tfsdk:"platform_tenant_id"`
+	PlatformTenantName         types.String `tfsdk:"platform_tenant_name"`
+	TenantType                 types.String `tfsdk:"tenant_type"`
+	CreatedAt                  types.String `tfsdk:"created_at"`
+	CreatedBy                  types.String `tfsdk:"created_by"`
+	LastUpdatedBy              types.String `tfsdk:"last_updated_by"`
+	LastUpdatedAt              types.String `tfsdk:"last_updated_at"`
+	IsDeleted                  types.Bool   `tfsdk:"is_deleted"`
+	PreviewContextId           types.String `tfsdk:"preview_context_id"`
+	LiveContextId              types.String `tfsdk:"live_context_id"`
+	HighAvailabilityEnabled    types.Bool   `tfsdk:"high_availability_enabled"`
+	ProvisioningStatus         types.String `tfsdk:"provisioning_status"`
+	ProvisioningFailureMessage types.String `tfsdk:"provisioning_failure_message"`
+	RepositoryBranch           types.String `tfsdk:"repository_branch"`
+	DeployOnCommit             types.Bool   `tfsdk:"deploy_on_commit"`
+	SitecoreMajorVersion       types.Int64  `tfsdk:"sitecore_major_version"`
+	SitecoreVersion            types.String `tfsdk:"sitecore_version"`
+	Region                     types.String `tfsdk:"region"`
 }
 
 // Metadata returns the data source type name
@@ -116,6 +123,34 @@ func (d *environmentDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				Description: "Whether high availability is enabled",
 				Computed:    true,
 			},
+			"provisioning_status": schema.StringAttribute{
+				Description: "The provisioning status of the environment, can have the values 'not_started', 'in_progress', 'complete', 'failed' or 'unknown'",
+				Computed:    true,
+			},
+			"provisioning_failure_message": schema.StringAttribute{
+				Description: "The reason the last provisioning of the environment failed",
+				Computed:    true,
+			},
+			"repository_branch": schema.StringAttribute{
+				Description: "The branch of the source code repository that is deployed to the environment",
+				Computed:    true,
+			},
+			"deploy_on_commit": schema.BoolAttribute{
+				Description: "Whether the environment is deployed automatically when commits are pushed to the repository branch",
+				Computed:    true,
+			},
+			"sitecore_major_version": schema.Int64Attribute{
+				Description: "The major version of Sitecore running in the environment",
+				Computed:    true,
+			},
+			"sitecore_version": schema.StringAttribute{
+				Description: "The version of Sitecore running in the environment in the major.minor format",
+				Computed:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the environment is hosted in",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -143,7 +178,7 @@ func (d *environmentDataSource) Read(ctx context.Context, req datasource.ReadReq
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading environments",
-			"Could not read environments for project "+state.ProjectID.ValueString()+": "+err.Error(),
+			"Could not read environments for project "+state.ProjectID.ValueString()+": "+errorDetail(err),
 		)
 		return
 	}
@@ -182,6 +217,13 @@ func (d *environmentDataSource) Read(ctx context.Context, req datasource.ReadReq
 	state.PreviewContextId = types.StringValue(foundEnvironment.PreviewContextId)
 	state.LiveContextId = types.StringValue(foundEnvironment.LiveContextId)
 	state.HighAvailabilityEnabled = types.BoolValue(foundEnvironment.HighAvailabilityEnabled)
+	state.ProvisioningStatus = types.StringValue(foundEnvironment.ProvisioningStatus.String())
+	state.ProvisioningFailureMessage = types.StringValue(foundEnvironment.ProvisioningLastFailureMessage)
+	state.RepositoryBranch = types.StringValue(foundEnvironment.RepositoryBranch)
+	state.DeployOnCommit = types.BoolValue(foundEnvironment.DeployOnCommit)
+	state.SitecoreMajorVersion = types.Int64Value(int64(foundEnvironment.SitecoreMajorVersion))
+	state.SitecoreVersion = types.StringValue(foundEnvironment.SitecoreVersion().String())
+	state.Region = types.StringValue(foundEnvironment.Zone)
 
 	// Set state
 	diags = resp.State.Set(ctx, &state)
@@ -0,0 +1,156 @@
+// Environment list resource implementation
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ list.ListResource              = &environmentListResource{}
+	_ list.ListResourceWithConfigure = &environmentListResource{}
+)
+
+// NewEnvironmentListResource lists combined environments
+func NewEnvironmentListResource() list.ListResource {
+	return &environmentListResource{
+		typeName:        "_environment",
+		kind:            "combined environments",
+		environmentType: apiclient.EnvironmentTypeCombined,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &environmentResource{client: client}
+		},
+	}
+}
+
+// NewCMEnvironmentListResource lists CM environments
+func NewCMEnvironmentListResource() list.ListResource {
+	return &environmentListResource{
+		typeName:        "_cm_environment",
+		kind:            "CM environments",
+		environmentType: apiclient.EnvironmentTypeCmOnly,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &cmEnvironmentResource{client: client}
+		},
+	}
+}
+
+// NewEHEnvironmentListResource lists editing host environments
+func NewEHEnvironmentListResource() list.ListResource {
+	return &environmentListResource{
+		typeName:        "_eh_environment",
+		kind:            "editing host environments",
+		environmentType: apiclient.EnvironmentTypeEhOnly,
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &ehEnvironmentResource{client: client}
+		},
+	}
+}
+
+// environmentListResource is the list resource implementation, listing the environments
+// of the type managed by the resource with the same type name
+type environmentListResource struct {
+	client          *apiclient.Client
+	typeName        string
+	kind            string
+	environmentType apiclient.EnvironmentType
+	newResource     func(client *apiclient.Client) resource.Resource
+}
+
+// environmentListResourceModel maps the list resource schema data
+type environmentListResourceModel struct {
+	ProjectID types.String `tfsdk:"project_id"`
+}
+
+// Metadata returns the resource type name
+func (r *environmentListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+// ListResourceConfigSchema defines the schema for the list resource
+func (r *environmentListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the " + r.kind + " of the organization. Deleted environments are not listed",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Description: "Only list the " + r.kind + " of the project with this ID. Defaults to all projects",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the list resource
+func (r *environmentListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	r.client = listResourceClient(req)
+}
+
+// List streams the environments of the listed projects
+func (r *environmentListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	if listClientMissing(r.client, stream) {
+		return
+	}
+
+	var config environmentListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	projects, err := listedProjects(r.client, config.ProjectID)
+	if err != nil {
+		listErrorResults(stream, "Error listing "+r.kind, "Could not read projects: "+errorDetail(err))
+		return
+	}
+
+	var listed []listedResource
+	for _, project := range projects {
+		environments, err := r.client.GetProjectEnvironments(project.ID)
+		if err != nil {
+			listErrorResults(stream, "Error listing "+r.kind, fmt.Sprintf("Could not read the environments of project %q: %s", project.Name, errorDetail(err)))
+			return
+		}
+
+		for _, environment := range environments {
+			if environment.IsDeleted || !isEnvironmentType(&environment, r.environmentType) {
+				continue
+			}
+
+			listed = append(listed, listedResource{
+				displayName: project.Name + "/" + environment.Name,
+				identity: environmentIdentityModel{
+					ProjectID:     types.StringValue(project.ID),
+					EnvironmentID: types.StringValue(environment.ID),
+				},
+				attributes: map[string]attr.Value{
+					"id": types.StringValue(environment.ID),
+				},
+			})
+		}
+	}
+
+	streamListResults(ctx, req, stream, r.newResource(r.client), listed)
+}
+
+// listedProjects returns the project with the given ID, or all projects when the ID is not set
+func listedProjects(client *apiclient.Client, projectID types.String) ([]apiclient.Project, error) {
+	if projectID.IsNull() {
+		return client.GetProjects()
+	}
+
+	project, err := client.GetProject(projectID.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	return []apiclient.Project{*project}, nil
+}
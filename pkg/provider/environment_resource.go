@@ -3,21 +3,31 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &environmentResource{}
-	_ resource.ResourceWithConfigure   = &environmentResource{}
-	_ resource.ResourceWithImportState = &environmentResource{}
+	_ resource.Resource                   = &environmentResource{}
+	_ resource.ResourceWithConfigure      = &environmentResource{}
+	_ resource.ResourceWithIdentity       = &environmentResource{}
+	_ resource.ResourceWithImportState    = &environmentResource{}
+	_ resource.ResourceWithModifyPlan     = &environmentResource{}
+	_ resource.ResourceWithValidateConfig = &environmentResource{}
+	_ resource.ResourceWithUpgradeState   = &environmentResource{}
 )
 
 // NewEnvironmentResource is a helper function to simplify the provider implementation
@@ -32,22 +42,32 @@ type environmentResource struct {
 
 // environmentResourceModel maps the resource schema data
 type environmentResourceModel struct {
-	ID                      types.String `tfsdk:"id"`
-	Name                    types.String `tfsdk:"name"`
-	ProjectID               types.String `tfsdk:"project_id"`
-	IsProd                  types.Bool   `tfsdk:"is_prod"`
-	TenantType              types.String `tfsdk:"tenant_type"`
-	Host                    types.String `tfsdk:"host"`
-	PlatformTenantId        types.String `tfsdk:"platform_tenant_id"`
-	PlatformTenantName      types.String `tfsdk:"platform_tenant_name"`
-	CreatedAt               types.String `tfsdk:"created_at"`
-	CreatedBy               types.String `tfsdk:"created_by"`
-	LastUpdatedBy           types.String `tfsdk:"last_updated_by"`
-	LastUpdatedAt           types.String `tfsdk:"last_updated_at"`
-	IsDeleted               types.Bool   `tfsdk:"is_deleted"`
-	PreviewContextId        types.String `tfsdk:"preview_context_id"`
-	LiveContextId           types.String `tfsdk:"live_context_id"`
-	HighAvailabilityEnabled types.Bool   `tfsdk:"high_availability_enabled"`
+	ID                         types.String   `tfsdk:"id"`
+	Name                       types.String   `tfsdk:"name"`
+	ProjectID                  types.String   `tfsdk:"project_id"`
+	IsProd                     types.Bool     `tfsdk:"is_prod"`
+	TenantType                 types.String   `tfsdk:"tenant_type"`
+	Host                       types.String   `tfsdk:"host"`
+	PlatformTenantId           types.String   `tfsdk:"platform_tenant_id"`
+	PlatformTenantName         types.String   `tfsdk:"platform_tenant_name"`
+	CreatedAt                  types.String   `tfsdk:"created_at"`
+	CreatedBy                  types.String   `tfsdk:"created_by"`
+	LastUpdatedBy              types.String   `tfsdk:"last_updated_by"`
+	LastUpdatedAt              types.String   `tfsdk:"last_updated_at"`
+	IsDeleted                  types.Bool     `tfsdk:"is_deleted"`
+	RestoreIfDeleted           types.Bool     `tfsdk:"restore_if_deleted"`
+	AdoptExisting              types.Bool     `tfsdk:"adopt_existing"`
+	PreviewContextId           types.String   `tfsdk:"preview_context_id"`
+	LiveContextId              types.String   `tfsdk:"live_context_id"`
+	HighAvailabilityEnabled    types.Bool     `tfsdk:"high_availability_enabled"`
+	ProvisioningStatus         types.String   `tfsdk:"provisioning_status"`
+	ProvisioningFailureMessage types.String   `tfsdk:"provisioning_failure_message"`
+	RepositoryBranch           types.String   `tfsdk:"repository_branch"`
+	DeployOnCommit             types.Bool     `tfsdk:"deploy_on_commit"`
+	SitecoreMajorVersion       types.Int64    `tfsdk:"sitecore_major_version"`
+	SitecoreVersion            types.String   `tfsdk:"sitecore_version"`
+	Region                     types.String   `tfsdk:"region"`
+	Timeouts                   timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name
@@ -56,8 +76,9 @@ func (r *environmentResource) Metadata(_ context.Context, req resource.MetadataR
 }
 
 // Schema defines the schema for the resource
-func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *environmentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: `Environments ¤ Manages a traditional SitecoreAI combined environment with both authoring and editing.`,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -79,8 +100,11 @@ func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 			"is_prod": schema.BoolAttribute{
-				Description: "Whether this is a production environment",
+				Description: "Whether this is a production environment. Changing it replaces the environment",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					isProdRequiresReplace(),
+				},
 			},
 			"tenant_type": schema.StringAttribute{
 				Description: "Indicates if it is production or not, can have the values 'prod' or 'nonprod'",
@@ -118,6 +142,11 @@ func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "Whether the environment is deleted",
 				Computed:    true,
 			},
+			"restore_if_deleted": schema.BoolAttribute{
+				Description: "Whether to restore the environment when it was deleted outside Terraform, instead of creating a new one. Defaults to false",
+				Optional:    true,
+			},
+			"adopt_existing": adoptExistingAttribute("environment", "in the project"),
 			"preview_context_id": schema.StringAttribute{
 				Description: "The preview context ID",
 				Computed:    true,
@@ -127,11 +156,131 @@ func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Computed:    true,
 			},
 			"high_availability_enabled": schema.BoolAttribute{
-				Description: "Whether high availability is enabled",
+				Description: "Whether high availability is enabled. Only production environments can be highly available",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"provisioning_status": schema.StringAttribute{
+				Description: "The provisioning status of the environment, can have the values 'not_started', 'in_progress', 'complete', 'failed' or 'unknown'",
+				Computed:    true,
+			},
+			"provisioning_failure_message": schema.StringAttribute{
+				Description: "The reason the last provisioning of the environment failed",
+				Computed:    true,
+			},
+			"repository_branch": schema.StringAttribute{
+				Description: "The branch of the source code repository that is deployed to the environment",
+				Optional:    true,
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deploy_on_commit": schema.BoolAttribute{
+				Description: "Whether the environment is deployed automatically when commits are pushed to the repository branch",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sitecore_major_version": schema.Int64Attribute{
+				Description: "The major version of Sitecore running in the environment",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"sitecore_version": schema.StringAttribute{
+				Description: "The version of Sitecore running in the environment in the major.minor format, see the sitecoreai_sitecore_versions data source. Changing it upgrades the environment",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the environment is hosted in, see the sitecoreai_regions data source. Defaults to the region of the organization, changing it forces a new environment",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that high availability is only requested where it is allowed,
+// and that the Sitecore version is valid
+func (r *environmentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var highAvailabilityEnabled, isProd types.Bool
+	var sitecoreVersion types.String
+	var sitecoreMajorVersion types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("high_availability_enabled"), &highAvailabilityEnabled)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("is_prod"), &isProd)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sitecore_version"), &sitecoreVersion)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sitecore_major_version"), &sitecoreMajorVersion)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateSitecoreVersionConfig(sitecoreVersion, sitecoreMajorVersion, &resp.Diagnostics)
+
+	// Values that are not known yet are checked when the plan is applied
+	if !highAvailabilityEnabled.ValueBool() || isProd.IsUnknown() {
+		return
+	}
+
+	if !isProd.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("high_availability_enabled"),
+			"High availability not allowed",
+			"High availability can only be enabled on production environments. Set is_prod = true, or remove high_availability_enabled.",
+		)
+	}
+}
+
+// IdentitySchema defines the identity of the resource
+func (r *environmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = environmentIdentitySchema()
+}
+
+// ModifyPlan checks that the project exists, that the name is not used by another
+// environment in the project, that a new region is available to the organization and
+// that a new Sitecore version is supported
+func (r *environmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the environment is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plannedRegion, currentRegion types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("region"), &plannedRegion)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("region"), &currentRegion)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
+
+	validateRegion(r.client, plannedRegion, currentRegion, &resp.Diagnostics)
+	validateEnvironmentPlan(ctx, r.client, req, resp)
+	modifyRestorePlan(ctx, req, resp)
+	modifySitecoreVersionPlan(ctx, r.client, req, resp)
 }
 
 // Configure adds the provider configured client to the resource
@@ -153,44 +302,112 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, r.client.Timeouts.Create)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Parse is_prod parameter (default: false)
 	isProd := false
 	if !plan.IsProd.IsNull() && !plan.IsProd.IsUnknown() {
 		isProd = plan.IsProd.ValueBool()
 	}
 
-	// Call API with Combined environment type
-	createdEnvironment, err := r.client.CreateEnvironment(
-		plan.ProjectID.ValueString(),
-		plan.Name.ValueString(),
-		isProd,
-		apiclient.EnvironmentTypeCombined,
-		"", // Combined environments don't need cmEnvironmentId
-	)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating environment",
-			"Could not create environment, unexpected error: "+err.Error(),
+	// Parse the optional environment settings, the API applies its defaults to the rest
+	settings := apiclient.EnvironmentSettings{}
+	if !plan.RepositoryBranch.IsNull() && !plan.RepositoryBranch.IsUnknown() {
+		settings.RepositoryBranch = plan.RepositoryBranch.ValueString()
+	}
+	if !plan.DeployOnCommit.IsNull() && !plan.DeployOnCommit.IsUnknown() {
+		settings.DeployOnCommit = plan.DeployOnCommit.ValueBool()
+	}
+	if !plan.SitecoreMajorVersion.IsNull() && !plan.SitecoreMajorVersion.IsUnknown() {
+		settings.SitecoreMajorVersion = int(plan.SitecoreMajorVersion.ValueInt64())
+	}
+	sitecoreVersion, upgrade := plannedSitecoreVersion(plan.SitecoreVersion)
+	if upgrade && settings.SitecoreMajorVersion == 0 {
+		settings.SitecoreMajorVersion = sitecoreVersion.MajorVersion
+	}
+	if !plan.Region.IsNull() && !plan.Region.IsUnknown() {
+		settings.Zone = plan.Region.ValueString()
+	}
+	if !plan.HighAvailabilityEnabled.IsNull() && !plan.HighAvailabilityEnabled.IsUnknown() {
+		settings.HighAvailabilityEnabled = plan.HighAvailabilityEnabled.ValueBool()
+	}
+
+	// Take over an existing environment with the same name when requested
+	var createdEnvironment *apiclient.Environment
+	var err error
+	if plan.AdoptExisting.ValueBool() {
+		majorVersion := plan.SitecoreMajorVersion
+		if upgrade {
+			// The upgrade below brings the environment to the configured version
+			majorVersion = types.Int64Null()
+		}
+		changes := adoptedEnvironmentChanges(plan.RepositoryBranch, plan.DeployOnCommit, majorVersion)
+		createdEnvironment, err = adoptEnvironment(ctx, r.client, plan.ProjectID.ValueString(), plan.Name.ValueString(), apiclient.EnvironmentTypeCombined, "", plan.IsProd, plan.Region, changes)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error adopting Environment",
+				"Could not take over the existing environment: "+errorDetail(err),
+			)
+			return
+		}
+	}
+
+	if createdEnvironment == nil {
+		// Call API with Combined environment type
+		createdEnvironment, err = r.client.CreateEnvironment(
+			ctx,
+			plan.ProjectID.ValueString(),
+			plan.Name.ValueString(),
+			isProd,
+			apiclient.EnvironmentTypeCombined,
+			"", // Combined environments don't need cmEnvironmentId
+			settings,
 		)
-		return
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating environment",
+				"Could not create environment, unexpected error: "+errorDetail(err),
+			)
+			return
+		}
+	}
+
+	// Wait for environment to be ready with context IDs until the create timeout
+	readyEnvironment, waitErr := r.client.WaitForEnvironmentReady(ctx, createdEnvironment.ID)
+	if readyEnvironment != nil {
+		// Use the latest environment instead of the initially created one
+		createdEnvironment = readyEnvironment
+	}
+
+	// Upgrade the environment when it was not created with the requested minor version
+	var upgradeErr error
+	if waitErr == nil && upgrade && createdEnvironment.SitecoreVersion().String() != sitecoreVersion.String() {
+		var upgradedEnvironment *apiclient.Environment
+		upgradedEnvironment, upgradeErr = r.client.UpgradeEnvironment(ctx, createdEnvironment.ID, sitecoreVersion)
+		if upgradedEnvironment != nil {
+			createdEnvironment = upgradedEnvironment
+		}
+	}
+
+	// Apply high availability once the environment is ready, in case it was not applied on create
+	var highAvailabilityErr error
+	if waitErr == nil && upgradeErr == nil && !plan.HighAvailabilityEnabled.IsNull() && !plan.HighAvailabilityEnabled.IsUnknown() {
+		var highAvailabilityEnvironment *apiclient.Environment
+		highAvailabilityEnvironment, highAvailabilityErr = r.client.SetHighAvailability(ctx, createdEnvironment.ID, plan.HighAvailabilityEnabled.ValueBool())
+		if highAvailabilityEnvironment != nil {
+			createdEnvironment = highAvailabilityEnvironment
+		}
 	}
 
 	// Map response body to schema and populate Computed attribute values
-	plan.ID = types.StringValue(createdEnvironment.ID)
-	plan.Name = types.StringValue(createdEnvironment.Name)
-	plan.ProjectID = types.StringValue(createdEnvironment.ProjectID)
-	plan.Host = types.StringValue(createdEnvironment.Host)
-	plan.PlatformTenantId = types.StringValue(createdEnvironment.PlatformTenantId)
-	plan.PlatformTenantName = types.StringValue(createdEnvironment.PlatformTenantName)
-	plan.TenantType = types.StringValue(createdEnvironment.TenantType)
-	plan.CreatedAt = types.StringValue(createdEnvironment.CreatedAt)
-	plan.CreatedBy = types.StringValue(createdEnvironment.CreatedBy)
-	plan.LastUpdatedBy = types.StringValue(createdEnvironment.LastUpdatedBy)
-	plan.LastUpdatedAt = types.StringValue(createdEnvironment.LastUpdatedAt)
-	plan.IsDeleted = types.BoolValue(createdEnvironment.IsDeleted)
-	plan.PreviewContextId = types.StringValue(createdEnvironment.PreviewContextId)
-	plan.LiveContextId = types.StringValue(createdEnvironment.LiveContextId)
-	plan.HighAvailabilityEnabled = types.BoolValue(createdEnvironment.HighAvailabilityEnabled)
+	setEnvironmentModel(createdEnvironment, &plan)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -198,6 +415,47 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.ID}, &resp.Diagnostics)
+
+	// The environment is saved even when it is not ready in time, so it is
+	// tainted and replaced on the next apply instead of being left untracked
+	var provisioningErr *apiclient.ProvisioningFailedError
+	if errors.As(waitErr, &provisioningErr) {
+		resp.Diagnostics.AddError(
+			"Environment provisioning failed",
+			"The environment was created but could not be provisioned. It has been marked as tainted and will be replaced on the next apply: "+provisioningErr.Error(),
+		)
+		return
+	}
+	if waitErr != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for environment to be ready",
+			"Could not wait for environment to be ready: "+errorDetail(waitErr),
+		)
+		return
+	}
+	if errors.As(upgradeErr, &provisioningErr) {
+		resp.Diagnostics.AddError(
+			"Environment upgrade failed",
+			"The environment was created but could not be upgraded to Sitecore "+sitecoreVersion.String()+". It has been marked as tainted and will be replaced on the next apply: "+provisioningErr.Error(),
+		)
+		return
+	}
+	if upgradeErr != nil {
+		resp.Diagnostics.AddError(
+			"Error upgrading environment",
+			"The environment was created but could not be upgraded to Sitecore "+sitecoreVersion.String()+". It has been marked as tainted and will be replaced on the next apply: "+errorDetail(upgradeErr),
+		)
+		return
+	}
+	if highAvailabilityErr != nil {
+		resp.Diagnostics.AddError(
+			"Error setting high availability on environment",
+			"The environment was created, but high availability could not be applied. It has been marked as tainted and will be replaced on the next apply: "+errorDetail(highAvailabilityErr),
+		)
+		return
+	}
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -210,38 +468,43 @@ func (r *environmentResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, r.client.Timeouts.Read)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get environment from API
-	environment, err := r.client.GetEnvironment(state.ID.ValueString())
+	environment, err := r.client.GetEnvironment(ctx, state.ID.ValueString())
+	if apiclient.IsNotFoundError(err) {
+		removeDeletedResource(ctx, resp, "Environment", state.ID.ValueString())
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading environment",
-			"Could not read environment ID "+state.ID.ValueString()+": "+err.Error(),
+			"Could not read environment ID "+state.ID.ValueString()+": "+errorDetail(err),
 		)
 		return
 	}
 
-	// Handle environment not found (soft delete scenario)
-	if environment == nil {
-		resp.State.RemoveResource(ctx)
+	// Soft deleted environments are gone as far as Terraform is concerned,
+	// unless they are restored on the next apply
+	if environment.IsDeleted && !state.RestoreIfDeleted.ValueBool() {
+		removeDeletedResource(ctx, resp, "Environment", state.ID.ValueString())
 		return
 	}
+	if environment.IsDeleted {
+		tflog.Warn(ctx, "Environment was deleted outside Terraform, it will be restored on the next apply", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+	}
 
 	// Overwrite items with refreshed state
-	state.Name = types.StringValue(environment.Name)
-	state.ProjectID = types.StringValue(environment.ProjectID)
-	state.Host = types.StringValue(environment.Host)
-	state.PlatformTenantId = types.StringValue(environment.PlatformTenantId)
-	state.PlatformTenantName = types.StringValue(environment.PlatformTenantName)
-	state.TenantType = types.StringValue(environment.TenantType)
-	state.IsProd = types.BoolValue(environment.TenantType == "prod")
-	state.CreatedAt = types.StringValue(environment.CreatedAt)
-	state.CreatedBy = types.StringValue(environment.CreatedBy)
-	state.LastUpdatedBy = types.StringValue(environment.LastUpdatedBy)
-	state.LastUpdatedAt = types.StringValue(environment.LastUpdatedAt)
-	state.IsDeleted = types.BoolValue(environment.IsDeleted)
-	state.PreviewContextId = types.StringValue(environment.PreviewContextId)
-	state.LiveContextId = types.StringValue(environment.LiveContextId)
-	state.HighAvailabilityEnabled = types.BoolValue(environment.HighAvailabilityEnabled)
+	setEnvironmentModel(environment, &state)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -249,6 +512,8 @@ func (r *environmentResource) Read(ctx context.Context, req resource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentIdentityModel{ProjectID: state.ProjectID, EnvironmentID: state.ID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -261,6 +526,15 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, r.client.Timeouts.Update)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Get current state
 	var state environmentResourceModel
 	diags = req.State.Get(ctx, &state)
@@ -269,51 +543,81 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	// Create the environment update
-	environment := apiclient.Environment{
-		ID:        plan.ID.ValueString(),
-		Name:      plan.Name.ValueString(),
-		ProjectID: plan.ProjectID.ValueString(),
+	// Restore the environment first when it was deleted outside Terraform
+	if state.IsDeleted.ValueBool() && plan.RestoreIfDeleted.ValueBool() {
+		_, err := r.client.RestoreEnvironment(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error restoring environment",
+				"Could not restore environment: "+errorDetail(err),
+			)
+			return
+		}
 	}
 
-	// Set tenant type if provided
-	if !plan.TenantType.IsNull() && !plan.TenantType.IsUnknown() {
-		environment.TenantType = plan.TenantType.ValueString()
+	// Only send the attributes the plan changes, the others keep their current values.
+	// Nothing is sent when the plan only changes attributes Terraform keeps to itself, such as timeouts
+	changes := apiclient.UpdateEnvironmentRequest{}
+	if !plan.Name.Equal(state.Name) {
+		changes.Name = plan.Name.ValueStringPointer()
+	}
+	if !plan.RepositoryBranch.IsUnknown() && !plan.RepositoryBranch.Equal(state.RepositoryBranch) {
+		changes.RepositoryBranch = plan.RepositoryBranch.ValueStringPointer()
+	}
+	if !plan.DeployOnCommit.IsUnknown() && !plan.DeployOnCommit.Equal(state.DeployOnCommit) {
+		changes.DeployOnCommit = plan.DeployOnCommit.ValueBoolPointer()
+	}
+	// A changed Sitecore version is applied by the upgrade below, which changes the major version as well
+	sitecoreVersion, upgrade := plannedSitecoreVersion(plan.SitecoreVersion)
+	upgrade = upgrade && !plan.SitecoreVersion.Equal(state.SitecoreVersion)
+	if !upgrade && !plan.SitecoreMajorVersion.IsUnknown() && !plan.SitecoreMajorVersion.Equal(state.SitecoreMajorVersion) {
+		sitecoreMajorVersion := int(plan.SitecoreMajorVersion.ValueInt64())
+		changes.SitecoreMajorVersion = &sitecoreMajorVersion
 	}
 
-	err := r.client.UpdateEnvironment(plan.ProjectID.ValueString(), plan.ID.ValueString(), environment)
+	updatedEnvironment, err := r.client.PatchEnvironment(ctx, plan.ID.ValueString(), changes)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating environment",
-			"Could not update environment, unexpected error: "+err.Error(),
+			"Could not update environment, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
 
-	// Fetch updated environment from API
-	updatedEnvironment, err := r.client.GetEnvironment(plan.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading updated environment",
-			"Could not read updated environment ID "+plan.ID.ValueString()+": "+err.Error(),
-		)
-		return
+	// Upgrades run through the Deploy API, wait for the upgrade to finish
+	if upgrade {
+		updatedEnvironment, err = r.client.UpgradeEnvironment(ctx, plan.ID.ValueString(), sitecoreVersion)
+		var provisioningErr *apiclient.ProvisioningFailedError
+		if errors.As(err, &provisioningErr) {
+			resp.Diagnostics.AddError(
+				"Environment upgrade failed",
+				"The environment could not be upgraded to Sitecore "+sitecoreVersion.String()+": "+provisioningErr.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error upgrading environment",
+				"Could not upgrade environment to Sitecore "+sitecoreVersion.String()+": "+errorDetail(err),
+			)
+			return
+		}
+	}
+
+	// High availability is provisioned separately, wait for the change to settle
+	if !plan.HighAvailabilityEnabled.IsUnknown() && !plan.HighAvailabilityEnabled.IsNull() && !plan.HighAvailabilityEnabled.Equal(state.HighAvailabilityEnabled) {
+		updatedEnvironment, err = r.client.SetHighAvailability(ctx, plan.ID.ValueString(), plan.HighAvailabilityEnabled.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error setting high availability on environment",
+				"Could not change high availability on environment: "+errorDetail(err),
+			)
+			return
+		}
 	}
 
 	// Update state with refreshed values
-	plan.Name = types.StringValue(updatedEnvironment.Name)
-	plan.Host = types.StringValue(updatedEnvironment.Host)
-	plan.PlatformTenantId = types.StringValue(updatedEnvironment.PlatformTenantId)
-	plan.PlatformTenantName = types.StringValue(updatedEnvironment.PlatformTenantName)
-	plan.TenantType = types.StringValue(updatedEnvironment.TenantType)
-	plan.CreatedAt = types.StringValue(updatedEnvironment.CreatedAt)
-	plan.CreatedBy = types.StringValue(updatedEnvironment.CreatedBy)
-	plan.LastUpdatedBy = types.StringValue(updatedEnvironment.LastUpdatedBy)
-	plan.LastUpdatedAt = types.StringValue(updatedEnvironment.LastUpdatedAt)
-	plan.IsDeleted = types.BoolValue(updatedEnvironment.IsDeleted)
-	plan.PreviewContextId = types.StringValue(updatedEnvironment.PreviewContextId)
-	plan.LiveContextId = types.StringValue(updatedEnvironment.LiveContextId)
-	plan.HighAvailabilityEnabled = types.BoolValue(updatedEnvironment.HighAvailabilityEnabled)
+	setEnvironmentModel(updatedEnvironment, &plan)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -321,6 +625,38 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, environmentIdentityModel{ProjectID: plan.ProjectID, EnvironmentID: plan.ID}, &resp.Diagnostics)
+}
+
+// setEnvironmentModel maps an environment returned by the API to the environment resource model
+func setEnvironmentModel(environment *apiclient.Environment, model *environmentResourceModel) {
+	model.ID = types.StringValue(environment.ID)
+	model.Name = types.StringValue(environment.Name)
+	model.ProjectID = types.StringValue(environment.ProjectID)
+	model.Host = types.StringValue(environment.Host)
+	model.PlatformTenantId = types.StringValue(environment.PlatformTenantId)
+	model.PlatformTenantName = types.StringValue(environment.PlatformTenantName)
+	model.TenantType = types.StringValue(environment.TenantType)
+	model.IsProd = isProdValue(model.IsProd, environment.TenantType)
+	model.CreatedAt = types.StringValue(environment.CreatedAt)
+	model.CreatedBy = types.StringValue(environment.CreatedBy)
+	model.LastUpdatedBy = types.StringValue(environment.LastUpdatedBy)
+	model.LastUpdatedAt = types.StringValue(environment.LastUpdatedAt)
+	model.IsDeleted = types.BoolValue(environment.IsDeleted)
+	model.PreviewContextId = types.StringValue(environment.PreviewContextId)
+	model.LiveContextId = types.StringValue(environment.LiveContextId)
+	model.HighAvailabilityEnabled = types.BoolValue(environment.HighAvailabilityEnabled)
+	model.ProvisioningStatus = types.StringValue(environment.ProvisioningStatus.String())
+	model.ProvisioningFailureMessage = types.StringValue(environment.ProvisioningLastFailureMessage)
+	model.RepositoryBranch = types.StringValue(environment.RepositoryBranch)
+	model.DeployOnCommit = types.BoolValue(environment.DeployOnCommit)
+	model.SitecoreMajorVersion = types.Int64Value(int64(environment.SitecoreMajorVersion))
+	model.SitecoreVersion = types.StringValue(environment.SitecoreVersion().String())
+	// Not every response includes the region, a missing one keeps the configured value
+	if environment.Zone != "" || model.Region.IsUnknown() {
+		model.Region = types.StringValue(environment.Zone)
+	}
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -333,20 +669,91 @@ func (r *environmentResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, r.client.Timeouts.Delete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Delete the environment
-	err := r.client.DeleteEnvironment(state.ID.ValueString())
+	err := r.client.DeleteEnvironment(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting environment",
-			"Could not delete environment, unexpected error: "+err.Error(),
+			"Could not delete environment, unexpected error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Wait until the environment is gone, so it can be recreated with the same name in the same apply
+	err = r.client.WaitForEnvironmentDeleted(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for environment to be deleted",
+			"Could not wait for environment to be deleted: "+errorDetail(err),
 		)
 		return
 	}
 }
 
+// isProdRequiresReplace replaces an environment when is_prod changes, as updates do not change
+// the tenant type. An unset value and false both mean a non-production environment
+func isProdRequiresReplace() planmodifier.Bool {
+	return boolplanmodifier.RequiresReplaceIf(
+		func(_ context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = req.PlanValue.IsUnknown() || req.PlanValue.ValueBool() != req.StateValue.ValueBool()
+		},
+		"Changing is_prod replaces the environment",
+		"Changing `is_prod` replaces the environment",
+	)
+}
+
+// isProdValue returns is_prod for the tenant type of an environment. Non-production environments
+// keep an unset value, as leaving is_prod unset in the configuration means the same
+func isProdValue(current types.Bool, tenantType string) types.Bool {
+	if tenantType == "" {
+		return current
+	}
+
+	isProd := strings.EqualFold(tenantType, "prod")
+	if !isProd && current.IsNull() {
+		return current
+	}
+
+	return types.BoolValue(isProd)
+}
+
 // ImportState imports an existing environment into Terraform state
 func (r *environmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	// Expected format: environment_id
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Take the environment ID from the identity when imported by identity
+	var identity environmentIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.EnvironmentID)...)
+		}
+		return
+	}
+
+	// Resolve the import ID and save it to the id attribute
+	// Expected format: environment_id or project/environment, where the project is a name or ID
+	id, err := resolveEnvironmentImportID(r.client, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing environment",
+			"Could not resolve import ID: "+errorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *environmentResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
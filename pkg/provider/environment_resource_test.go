@@ -2,9 +2,19 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 func TestEnvironmentResourceMetadata(t *testing.T) {
@@ -40,6 +50,10 @@ func TestEnvironmentResourceSchema(t *testing.T) {
 		t.Error("Expected schema to have id attribute")
 	}
 
+	if _, ok := resp.Schema.Blocks["timeouts"]; !ok {
+		t.Error("Expected schema to have timeouts block")
+	}
+
 	if _, ok := resp.Schema.Attributes["name"]; !ok {
 		t.Error("Expected schema to have name attribute")
 	}
@@ -79,3 +93,342 @@ func TestEnvironmentResourceConfigure(t *testing.T) {
 //  	resp := resource.ImportStateResponse{}
 //  	r.ImportState(context.Background(), req, &resp)
 // }
+
+func TestEnvironmentResourceReadRemovesDeletedEnvironment(t *testing.T) {
+	tests := map[string]struct {
+		status           int
+		body             string
+		restoreIfDeleted bool
+		expected         bool
+	}{
+		"not found": {
+			status:   http.StatusNotFound,
+			body:     `{"title": "Not Found"}`,
+			expected: false,
+		},
+		"soft deleted": {
+			status:   http.StatusOK,
+			body:     `{"id": "test-environment-id", "name": "staging", "isDeleted": true}`,
+			expected: false,
+		},
+		"soft deleted and restored on the next apply": {
+			status:           http.StatusOK,
+			body:             `{"id": "test-environment-id", "name": "staging", "isDeleted": true}`,
+			restoreIfDeleted: true,
+			expected:         true,
+		},
+		"exists": {
+			status:   http.StatusOK,
+			body:     `{"id": "test-environment-id", "name": "staging"}`,
+			expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = fmt.Fprint(w, test.body)
+			}))
+			defer server.Close()
+
+			r := &environmentResource{client: &apiclient.Client{
+				BaseURL:    server.URL,
+				HTTPClient: server.Client(),
+				Token:      "test-token",
+				Timeouts:   apiclient.DefaultTimeouts,
+			}}
+
+			config := resourceConfig(t, r, map[string]tftypes.Value{
+				"id":                 tftypes.NewValue(tftypes.String, "test-environment-id"),
+				"restore_if_deleted": tftypes.NewValue(tftypes.Bool, test.restoreIfDeleted),
+			})
+			state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+			resp := resource.ReadResponse{State: state}
+
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+			}
+			if resp.State.Raw.IsNull() == test.expected {
+				t.Errorf("Expected resource to be kept in the state: %v", test.expected)
+			}
+		})
+	}
+}
+
+// resourceConfig builds a resource configuration with the given attribute values, others are null
+func resourceConfig(t *testing.T, r resource.Resource, values map[string]tftypes.Value) tfsdk.Config {
+	ctx := context.Background()
+
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("Expected resource schema to be an object")
+	}
+
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objectType, attributes),
+	}
+}
+
+func TestEnvironmentResourceValidateConfig(t *testing.T) {
+	tests := map[string]struct {
+		isProd           tftypes.Value
+		highAvailability tftypes.Value
+		expectError      bool
+	}{
+		"high availability on production": {
+			isProd:           tftypes.NewValue(tftypes.Bool, true),
+			highAvailability: tftypes.NewValue(tftypes.Bool, true),
+			expectError:      false,
+		},
+		"high availability on non-production": {
+			isProd:           tftypes.NewValue(tftypes.Bool, false),
+			highAvailability: tftypes.NewValue(tftypes.Bool, true),
+			expectError:      true,
+		},
+		"high availability without is_prod": {
+			isProd:           tftypes.NewValue(tftypes.Bool, nil),
+			highAvailability: tftypes.NewValue(tftypes.Bool, true),
+			expectError:      true,
+		},
+		"high availability disabled on non-production": {
+			isProd:           tftypes.NewValue(tftypes.Bool, false),
+			highAvailability: tftypes.NewValue(tftypes.Bool, false),
+			expectError:      false,
+		},
+		"is_prod not known yet": {
+			isProd:           tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+			highAvailability: tftypes.NewValue(tftypes.Bool, true),
+			expectError:      false,
+		},
+	}
+
+	resources := map[string]resource.ResourceWithValidateConfig{
+		"environment":    &environmentResource{},
+		"cm_environment": &cmEnvironmentResource{},
+	}
+
+	for resourceName, r := range resources {
+		for name, test := range tests {
+			t.Run(resourceName+" "+name, func(t *testing.T) {
+				req := resource.ValidateConfigRequest{
+					Config: resourceConfig(t, r, map[string]tftypes.Value{
+						"is_prod":                   test.isProd,
+						"high_availability_enabled": test.highAvailability,
+					}),
+				}
+				resp := resource.ValidateConfigResponse{}
+
+				r.ValidateConfig(context.Background(), req, &resp)
+
+				if resp.Diagnostics.HasError() != test.expectError {
+					t.Errorf("Expected error: %v, got diagnostics: %v", test.expectError, resp.Diagnostics)
+				}
+			})
+		}
+	}
+}
+
+func TestEnvironmentResourceValidateConfigSitecoreVersion(t *testing.T) {
+	tests := map[string]struct {
+		sitecoreVersion      tftypes.Value
+		sitecoreMajorVersion tftypes.Value
+		expectError          bool
+	}{
+		"version only": {
+			sitecoreVersion:      tftypes.NewValue(tftypes.String, "1.5"),
+			sitecoreMajorVersion: tftypes.NewValue(tftypes.Number, nil),
+			expectError:          false,
+		},
+		"matching major version": {
+			sitecoreVersion:      tftypes.NewValue(tftypes.String, "1.5"),
+			sitecoreMajorVersion: tftypes.NewValue(tftypes.Number, 1),
+			expectError:          false,
+		},
+		"conflicting major version": {
+			sitecoreVersion:      tftypes.NewValue(tftypes.String, "2.0"),
+			sitecoreMajorVersion: tftypes.NewValue(tftypes.Number, 1),
+			expectError:          true,
+		},
+		"invalid format": {
+			sitecoreVersion:      tftypes.NewValue(tftypes.String, "latest"),
+			sitecoreMajorVersion: tftypes.NewValue(tftypes.Number, nil),
+			expectError:          true,
+		},
+		"version not known yet": {
+			sitecoreVersion:      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			sitecoreMajorVersion: tftypes.NewValue(tftypes.Number, 1),
+			expectError:          false,
+		},
+	}
+
+	resources := map[string]resource.ResourceWithValidateConfig{
+		"environment":    &environmentResource{},
+		"cm_environment": &cmEnvironmentResource{},
+	}
+
+	for resourceName, r := range resources {
+		for name, test := range tests {
+			t.Run(resourceName+" "+name, func(t *testing.T) {
+				req := resource.ValidateConfigRequest{
+					Config: resourceConfig(t, r, map[string]tftypes.Value{
+						"sitecore_version":       test.sitecoreVersion,
+						"sitecore_major_version": test.sitecoreMajorVersion,
+					}),
+				}
+				resp := resource.ValidateConfigResponse{}
+
+				r.ValidateConfig(context.Background(), req, &resp)
+
+				if resp.Diagnostics.HasError() != test.expectError {
+					t.Errorf("Expected error: %v, got diagnostics: %v", test.expectError, resp.Diagnostics)
+				}
+			})
+		}
+	}
+}
+
+func TestIsProdValue(t *testing.T) {
+	tests := map[string]struct {
+		current    types.Bool
+		tenantType string
+		expected   types.Bool
+	}{
+		"production":                    {current: types.BoolNull(), tenantType: "prod", expected: types.BoolValue(true)},
+		"non-production left unset":     {current: types.BoolNull(), tenantType: "nonprod", expected: types.BoolNull()},
+		"non-production set explicitly": {current: types.BoolValue(false), tenantType: "nonprod", expected: types.BoolValue(false)},
+		"changed to non-production":     {current: types.BoolValue(true), tenantType: "nonprod", expected: types.BoolValue(false)},
+		"unknown tenant type":           {current: types.BoolValue(true), tenantType: "", expected: types.BoolValue(true)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := isProdValue(test.current, test.tenantType); !actual.Equal(test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEnvironmentResourcesUpdateIsProd(t *testing.T) {
+	tests := map[string]struct {
+		state    types.Bool
+		plan     types.Bool
+		expected bool
+	}{
+		"changed to production":       {state: types.BoolNull(), plan: types.BoolValue(true), expected: true},
+		"changed to non-production":   {state: types.BoolValue(true), plan: types.BoolValue(false), expected: true},
+		"non-production set to false": {state: types.BoolNull(), plan: types.BoolValue(false), expected: false},
+		"production unset":            {state: types.BoolValue(true), plan: types.BoolNull(), expected: true},
+		"unknown":                     {state: types.BoolNull(), plan: types.BoolUnknown(), expected: true},
+	}
+
+	resources := map[string]resource.Resource{
+		"environment":    &environmentResource{},
+		"cm_environment": &cmEnvironmentResource{},
+		"eh_environment": &ehEnvironmentResource{},
+	}
+
+	for resourceName, r := range resources {
+		for name, test := range tests {
+			t.Run(resourceName+"/"+name, func(t *testing.T) {
+				attribute := resourceSchemaAttribute(t, r, "is_prod").(schema.BoolAttribute)
+				state := resourceConfig(t, r, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "test-environment-id")})
+
+				req := planmodifier.BoolRequest{
+					Path:       path.Root("is_prod"),
+					StateValue: test.state,
+					PlanValue:  test.plan,
+					State:      tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+					Plan:       tfsdk.Plan{Schema: state.Schema, Raw: state.Raw},
+				}
+				resp := &planmodifier.BoolResponse{PlanValue: test.plan}
+				for _, modifier := range attribute.PlanModifiers {
+					modifier.PlanModifyBool(context.Background(), req, resp)
+				}
+
+				if resp.RequiresReplace != test.expected {
+					t.Errorf("Expected requires replace: %v, got %v", test.expected, resp.RequiresReplace)
+				}
+			})
+		}
+	}
+}
+
+func TestEHEnvironmentResourceUpdateCMEnvironmentID(t *testing.T) {
+	r := &ehEnvironmentResource{}
+	attribute := resourceSchemaAttribute(t, r, "cm_environment_id").(schema.StringAttribute)
+	state := resourceConfig(t, r, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "test-environment-id")})
+
+	req := planmodifier.StringRequest{
+		Path:       path.Root("cm_environment_id"),
+		StateValue: types.StringValue("cm-environment-1"),
+		PlanValue:  types.StringValue("cm-environment-2"),
+		State:      tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+		Plan:       tfsdk.Plan{Schema: state.Schema, Raw: state.Raw},
+	}
+	resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+	for _, modifier := range attribute.PlanModifiers {
+		modifier.PlanModifyString(context.Background(), req, resp)
+	}
+
+	if !resp.RequiresReplace {
+		t.Error("Expected linking another CM environment to replace the EH environment")
+	}
+}
+
+func TestCMEnvironmentResourceDeleteAlreadyDeleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The environment was deleted outside Terraform
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r := &cmEnvironmentResource{client: &apiclient.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+		Timeouts:   apiclient.DefaultTimeouts,
+	}}
+
+	config := resourceConfig(t, r, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "test-environment-id"),
+		"project_id": tftypes.NewValue(tftypes.String, "test-project-id"),
+		"name":       tftypes.NewValue(tftypes.String, "authoring"),
+	})
+	state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+	resp := resource.DeleteResponse{State: state}
+
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Errorf("Expected an environment that is already gone to be deleted, got %v", resp.Diagnostics)
+	}
+}
+
+// resourceSchemaAttribute returns an attribute of the resource schema
+func resourceSchemaAttribute(t *testing.T, r resource.Resource, name string) schema.Attribute {
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+
+	attribute, ok := schemaResp.Schema.Attributes[name]
+	if !ok {
+		t.Fatalf("Expected attribute %s in the schema", name)
+	}
+
+	return attribute
+}
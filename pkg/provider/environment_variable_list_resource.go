@@ -0,0 +1,170 @@
+// Environment variable list resource implementation
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &environmentVariableListResource{}
+	_ list.ListResourceWithConfigure = &environmentVariableListResource{}
+)
+
+// NewEnvironmentVariableListResource lists environment variables of any target.
+func NewEnvironmentVariableListResource() list.ListResource {
+	return &environmentVariableListResource{
+		typeName: "_environment_variable",
+		kind:     "environment variables",
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &environmentVariableResource{base: baseEnvironmentVariableResource{client: client}}
+		},
+	}
+}
+
+// NewCMEnvironmentVariableListResource lists environment variables with target CM.
+func NewCMEnvironmentVariableListResource() list.ListResource {
+	return &environmentVariableListResource{
+		typeName: "_cm_environment_variable",
+		kind:     "CM environment variables",
+		target:   "CM",
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &cmEnvironmentVariableResource{base: baseEnvironmentVariableResource{client: client}}
+		},
+	}
+}
+
+// NewEHEnvironmentVariableListResource lists environment variables with target EH.
+func NewEHEnvironmentVariableListResource() list.ListResource {
+	return &environmentVariableListResource{
+		typeName: "_eh_environment_variable",
+		kind:     "editing host environment variables",
+		target:   "EH",
+		newResource: func(client *apiclient.Client) resource.Resource {
+			return &ehEnvironmentVariableResource{base: baseEnvironmentVariableResource{client: client}}
+		},
+	}
+}
+
+// environmentVariableListResource is the list resource implementation. The target is
+// fixed for the CM and editing host variables, and a filter for the other variables.
+type environmentVariableListResource struct {
+	client      *apiclient.Client
+	typeName    string
+	kind        string
+	target      string
+	newResource func(client *apiclient.Client) resource.Resource
+}
+
+// Metadata returns the resource type name.
+func (r *environmentVariableListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+// ListResourceConfigSchema defines the schema for the list resource.
+func (r *environmentVariableListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the " + r.kind + " of an environment.",
+		Attributes: map[string]schema.Attribute{
+			"environment_id": schema.StringAttribute{
+				Description: "The ID of the environment to list the variables of.",
+				Required:    true,
+			},
+		},
+	}
+
+	if r.target == "" {
+		resp.Schema.Attributes["target"] = schema.StringAttribute{
+			Description: "Only list the variables with this target (CM, EH, or custom editing host name). Defaults to all variables.",
+			Optional:    true,
+		}
+	}
+}
+
+// Configure adds the provider-configured client to the list resource.
+func (r *environmentVariableListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	r.client = listResourceClient(req)
+}
+
+// List streams the variables of the environment.
+func (r *environmentVariableListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	if listClientMissing(r.client, stream) {
+		return
+	}
+
+	var environmentID, target types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("environment_id"), &environmentID)
+	if r.target == "" {
+		diags.Append(req.Config.GetAttribute(ctx, path.Root("target"), &target)...)
+	} else {
+		target = types.StringValue(r.target)
+	}
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	variables, err := r.client.GetEnvironmentVariables(environmentID.ValueString())
+	if err != nil {
+		listErrorResults(stream, "Error listing "+r.kind, "Could not read environment variables: "+errorDetail(err))
+		return
+	}
+
+	var listed []listedResource
+	for _, variable := range variables {
+		if !target.IsNull() && variable.Target != target.ValueString() {
+			continue
+		}
+
+		listed = append(listed, r.listedVariable(environmentID.ValueString(), variable))
+	}
+
+	streamListResults(ctx, req, stream, r.newResource(r.client), listed)
+}
+
+// listedVariable returns the identity and import attributes of a variable.
+func (r *environmentVariableListResource) listedVariable(environmentID string, variable apiclient.EnvironmentVariable) listedResource {
+	if r.target != "" {
+		return listedResource{
+			displayName: variable.Name,
+			identity: variableIdentityModel{
+				EnvironmentID: types.StringValue(environmentID),
+				Name:          types.StringValue(variable.Name),
+			},
+			attributes: map[string]attr.Value{
+				"id":             types.StringValue(fmt.Sprintf("%s:%s", environmentID, variable.Name)),
+				"environment_id": types.StringValue(environmentID),
+				"name":           types.StringValue(variable.Name),
+			},
+		}
+	}
+
+	displayName := variable.Name
+	if variable.Target != "" {
+		displayName += "@" + variable.Target
+	}
+
+	return listedResource{
+		displayName: displayName,
+		identity: targetedVariableIdentityModel{
+			EnvironmentID: types.StringValue(environmentID),
+			Name:          types.StringValue(variable.Name),
+			Target:        optionalString(variable.Target),
+		},
+		attributes: map[string]attr.Value{
+			"id":             types.StringValue(environmentVariableID(environmentID, variable.Target, variable.Name)),
+			"environment_id": types.StringValue(environmentID),
+			"name":           types.StringValue(variable.Name),
+			"target":         optionalString(variable.Target),
+		},
+	}
+}
@@ -9,14 +9,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &environmentVariableResource{}
-	_ resource.ResourceWithConfigure   = &environmentVariableResource{}
-	_ resource.ResourceWithImportState = &environmentVariableResource{}
+	_ resource.Resource                 = &environmentVariableResource{}
+	_ resource.ResourceWithConfigure    = &environmentVariableResource{}
+	_ resource.ResourceWithIdentity     = &environmentVariableResource{}
+	_ resource.ResourceWithImportState  = &environmentVariableResource{}
+	_ resource.ResourceWithUpgradeState = &environmentVariableResource{}
 )
 
 // environmentVariableResourceModel maps the resource schema data.
@@ -38,6 +39,9 @@ func NewEnvironmentVariableResource() resource.Resource {
 // Metadata returns the resource type name.
 func (r *environmentVariableResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_environment_variable"
+
+	// Variables are renamed and moved to other environments in place.
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource.
@@ -53,6 +57,11 @@ func (r *environmentVariableResource) Schema(ctx context.Context, req resource.S
 	resp.Schema.Description = "Environments ¤ Manages an environment variable for SitecoreAI environment."
 }
 
+// IdentitySchema defines the identity of the resource.
+func (r *environmentVariableResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = variableIdentitySchema(true)
+}
+
 // Configure adds the provider-configured client to the resource.
 func (r *environmentVariableResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	r.base.Configure(ctx, req, resp)
@@ -68,25 +77,20 @@ func (r *environmentVariableResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	// Delegate to the base Create method with the target field
+	// Delegate to the base resource with the target field
 	target := plan.Target.ValueString()
-	r.base.Create(ctx, resource.CreateRequest{Plan: req.Plan}, resp, target)
+	r.base.create(&plan.baseEnvironmentVariableResourceModel, target, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Generate composite ID: environment_id:target:name
-	var compositeID string
-	if target == "" {
-		compositeID = fmt.Sprintf("%s::%s", plan.EnvironmentID.ValueString(), plan.Name.ValueString())
-	} else {
-		compositeID = fmt.Sprintf("%s:%s:%s", plan.EnvironmentID.ValueString(), target, plan.Name.ValueString())
-	}
-	plan.ID = types.StringValue(compositeID)
+	plan.ID = types.StringValue(environmentVariableID(plan.EnvironmentID.ValueString(), target, plan.Name.ValueString()))
 
 	// Set the state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	setIdentity(ctx, resp.Identity, targetedVariableIdentityModel{EnvironmentID: plan.EnvironmentID, Name: plan.Name, Target: plan.Target}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -99,78 +103,130 @@ func (r *environmentVariableResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
-	// Delegate to the base Update method with the target field
+	// Delegate to the base resource with the target field
 	target := plan.Target.ValueString()
-	r.base.Update(ctx, resource.UpdateRequest{Plan: req.Plan}, resp, target)
+	r.base.update(&plan.baseEnvironmentVariableResourceModel, target, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Keep the composite ID: environment_id:target:name
+	plan.ID = types.StringValue(environmentVariableID(plan.EnvironmentID.ValueString(), target, plan.Name.ValueString()))
+
 	// Set the state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	setIdentity(ctx, resp.Identity, targetedVariableIdentityModel{EnvironmentID: plan.EnvironmentID, Name: plan.Name, Target: plan.Target}, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *environmentVariableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Delegate to the base Read method
-	r.base.Read(ctx, req, resp)
+	// Get current state
+	var state environmentVariableResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delegate to the base resource with the target field
+	found := r.base.read(&state.baseEnvironmentVariableResourceModel, state.Target.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		removeDeletedResource(ctx, resp, "Environment variable", state.ID.ValueString())
+		return
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	setIdentity(ctx, resp.Identity, targetedVariableIdentityModel{EnvironmentID: state.EnvironmentID, Name: state.Name, Target: state.Target}, &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *environmentVariableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// Delegate to the base Delete method
-	r.base.Delete(ctx, req, resp)
+	// Retrieve values from state
+	var state environmentVariableResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delegate to the base resource
+	r.base.delete(&state.baseEnvironmentVariableResourceModel, &resp.Diagnostics)
+}
+
+// environmentVariableID returns the composite ID of a variable: environment_id:target:name
+func environmentVariableID(environmentID string, target string, name string) string {
+	return fmt.Sprintf("%s:%s:%s", environmentID, target, name)
 }
 
 // ImportState imports an existing environment variable into Terraform state.
 func (r *environmentVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Expected format: environment_id:target:name (target is optional)
-	idParts := strings.Split(req.ID, ":")
-	if len(idParts) < 2 || idParts[0] == "" || idParts[len(idParts)-1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid import ID format",
-			"Expected format: environment_id:target:name (target is optional)",
-		)
-		return
-	}
+	// Expected format: environment_id:target:name (target is optional),
+	// or project/environment/name@target (target is optional), or the identity
+	var environmentID, variableName, target string
+	var identity targetedVariableIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-	environmentID := idParts[0]
-	variableName := idParts[len(idParts)-1]
-	target := ""
-	if len(idParts) == 3 {
-		target = idParts[1]
+		environmentID = identity.EnvironmentID.ValueString()
+		variableName = identity.Name.ValueString()
+		target = identity.Target.ValueString()
+	} else if strings.Contains(req.ID, "/") {
+		var err error
+		environmentID, variableName, target, err = resolveVariableImportID(r.base.client, req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid import ID",
+				"Could not resolve import ID: "+errorDetail(err),
+			)
+			return
+		}
+	} else {
+		idParts := strings.Split(req.ID, ":")
+		if len(idParts) < 2 || idParts[0] == "" || idParts[len(idParts)-1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid import ID format",
+				"Expected format: environment_id:target:name (target is optional), or project/environment/name@target (target is optional)",
+			)
+			return
+		}
+
+		environmentID = idParts[0]
+		variableName = idParts[len(idParts)-1]
+		if len(idParts) == 3 {
+			target = idParts[1]
+		}
 	}
 
 	// Generate composite ID: environment_id:target:name
-	compositeID := fmt.Sprintf("%s:%s:%s", environmentID, target, variableName)
+	compositeID := environmentVariableID(environmentID, target, variableName)
 
 	// Set the composite ID and individual attributes
 	var state environmentVariableResourceModel
 	state.ID = types.StringValue(compositeID)
 	state.EnvironmentID = types.StringValue(environmentID)
 	state.Name = types.StringValue(variableName)
-	state.Target = types.StringValue(target)
+	state.Target = optionalString(target)
 
 	// Fetch the variable value from the API to ensure it exists
 	variables, err := r.base.client.GetEnvironmentVariables(environmentID)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading environment variables during import",
-			"Could not read environment variables: "+err.Error(),
+			"Could not read environment variables: "+errorDetail(err),
 		)
 		return
 	}
 
 	// Find our specific variable
-	var foundVariable *apiclient.EnvironmentVariable
-	for _, variable := range variables {
-		if variable.Name == variableName && (target == "" || variable.Target == target) {
-			foundVariable = &variable
-			break
-		}
-	}
-
+	foundVariable := findEnvironmentVariable(variables, variableName, target)
 	if foundVariable == nil {
 		resp.Diagnostics.AddError(
 			"Environment variable not found",
@@ -192,3 +248,10 @@ func (r *environmentVariableResource) ImportState(ctx context.Context, req resou
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
+
+// UpgradeState upgrades state written with earlier schema versions.
+func (r *environmentVariableResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(migrateVariableID(true)),
+	}
+}
@@ -2,9 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 func TestEnvironmentVariableResourceMetadata(t *testing.T) {
@@ -67,3 +73,107 @@ func TestEnvironmentVariableResourceConfigure(t *testing.T) {
 		t.Error("Expected client to remain nil when no provider data is provided")
 	}
 }
+
+func TestEnvironmentVariableResourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `[
+			{"name": "API_KEY", "value": "editing host", "secret": false, "target": "EH"},
+			{"name": "API_KEY", "value": "secret", "secret": true, "target": "CM"}
+		]`)
+	}))
+	defer server.Close()
+
+	r := &environmentVariableResource{base: baseEnvironmentVariableResource{client: &apiclient.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}}}
+
+	config := resourceConfig(t, r, map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "environment:CM:API_KEY"),
+		"environment_id": tftypes.NewValue(tftypes.String, "environment"),
+		"name":           tftypes.NewValue(tftypes.String, "API_KEY"),
+		"target":         tftypes.NewValue(tftypes.String, "CM"),
+		"value":          tftypes.NewValue(tftypes.String, "outdated"),
+	})
+	state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+	resp := resource.ReadResponse{State: state}
+
+	r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var refreshed environmentVariableResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &refreshed)...)
+	if refreshed.SecretValue.ValueString() != "secret" || !refreshed.Value.IsNull() {
+		t.Errorf("Expected the secret value of the CM variable, got value '%s' and secret value '%s'", refreshed.Value.ValueString(), refreshed.SecretValue.ValueString())
+	}
+	if refreshed.Target.ValueString() != "CM" {
+		t.Errorf("Expected target 'CM', got '%s'", refreshed.Target.ValueString())
+	}
+}
+
+func TestFindEnvironmentVariable(t *testing.T) {
+	variables := []apiclient.EnvironmentVariable{
+		{Name: "API_KEY", Value: "editing host", Target: "EH"},
+		{Name: "API_KEY", Value: "all", Target: ""},
+		{Name: "API_KEY", Value: "authoring", Target: "CM"},
+		{Name: "TOKEN", Value: "editing host", Target: "EH"},
+		{Name: "SECRET", Value: "editing host", Target: "EH"},
+		{Name: "SECRET", Value: "authoring", Target: "CM"},
+	}
+
+	tests := map[string]struct {
+		name     string
+		target   string
+		expected string
+	}{
+		"exact target":                {name: "API_KEY", target: "CM", expected: "authoring"},
+		"exact match without target":  {name: "API_KEY", target: "", expected: "all"},
+		"only variable with the name": {name: "TOKEN", target: "", expected: "editing host"},
+		"other target":                {name: "TOKEN", target: "CM"},
+		"several targets, none exact": {name: "SECRET", target: ""},
+		"missing":                     {name: "MISSING", target: ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			found := findEnvironmentVariable(variables, test.name, test.target)
+
+			if test.expected == "" {
+				if found != nil {
+					t.Errorf("Expected no variable, got %+v", found)
+				}
+				return
+			}
+			if found == nil || found.Value != test.expected {
+				t.Errorf("Expected the variable with value '%s', got %+v", test.expected, found)
+			}
+		})
+	}
+}
+
+func TestEnvironmentVariableResourceImportStateWithoutTarget(t *testing.T) {
+	r := &environmentVariableResource{base: baseEnvironmentVariableResource{client: fixtureClient(t, fixtureRoutes{
+		"/api/environments/v1/environment/variables": `[{"name": "API_KEY", "value": "editing host", "secret": false, "target": "EH"}]`,
+	})}}
+	resp := importStateResponse(r)
+
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "environment:API_KEY"}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var state environmentVariableResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.Value.ValueString() != "editing host" {
+		t.Errorf("Expected the value of the variable with a target, got '%s'", state.Value.ValueString())
+	}
+	if !state.Target.IsNull() {
+		t.Errorf("Expected the target to stay unset, got '%s'", state.Target.ValueString())
+	}
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// fixtureRoutes holds the responses of the fixture server by request path
+type fixtureRoutes map[string]string
+
+// fixtureResponses are the default responses of the fixture server, project XMC holding
+// CM environment "authoring" and combined environment "staging", without any clients
+var fixtureResponses = fixtureRoutes{
+	"/api/projects/v1":                      `[{"id": "project", "name": "XMC"}]`,
+	"/api/projects/v1/project":              `{"id": "project", "name": "XMC"}`,
+	"/api/projects/v2/project/environments": `[{"id": "cm", "name": "authoring", "projectId": "project", "type": "cm"}, {"id": "combined", "name": "staging", "projectId": "project"}]`,
+	"/api/environments/v2/cm":               `{"id": "cm", "name": "authoring", "projectId": "project", "type": "cm"}`,
+	"/api/environments/v2/combined":         `{"id": "combined", "name": "staging", "projectId": "project"}`,
+	"/api/clients/v1/environment":           `{"items": []}`,
+}
+
+// fixtureClient returns a client for a server with the fixture responses. The routes add
+// responses or replace the default ones, requests for other paths are not found
+func fixtureClient(t *testing.T, routes fixtureRoutes) *apiclient.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := routes[r.URL.Path]
+		if !ok {
+			response, ok = fixtureResponses[r.URL.Path]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	return &apiclient.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}
+}
@@ -0,0 +1,83 @@
+// Resolution of human-readable import IDs to the IDs of projects, environments and variables
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// resolveProjectImportID returns the ID of the project named by an import ID. IDs that do
+// not name a project are returned as is, so projects can still be imported by their ID
+func resolveProjectImportID(client *apiclient.Client, importID string) (string, error) {
+	project, err := client.FindProject(importID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up project %q: %w", importID, err)
+	}
+	if project == nil {
+		return importID, nil
+	}
+
+	return project.ID, nil
+}
+
+// resolveEnvironmentImportID returns the ID of the environment named by an import ID of
+// the form project/environment, where the project is given by its name or ID. Other
+// import IDs are returned as is, so environments can still be imported by their ID
+func resolveEnvironmentImportID(client *apiclient.Client, importID string) (string, error) {
+	parts := strings.Split(importID, "/")
+	if len(parts) == 1 {
+		return importID, nil
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("expected an environment ID or project/environment, got %q", importID)
+	}
+
+	return findEnvironmentByName(client, parts[0], parts[1])
+}
+
+// resolveVariableImportID returns the environment ID, name and target of the variable named by
+// an import ID of the form project/environment/NAME or project/environment/NAME@target
+func resolveVariableImportID(client *apiclient.Client, importID string) (environmentID string, name string, target string, err error) {
+	parts := strings.Split(importID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("expected project/environment/NAME or project/environment/NAME@target, got %q", importID)
+	}
+
+	name = parts[2]
+	if index := strings.LastIndex(name, "@"); index >= 0 {
+		name, target = name[:index], name[index+1:]
+		if name == "" || target == "" {
+			return "", "", "", fmt.Errorf("expected project/environment/NAME@target, got %q", importID)
+		}
+	}
+
+	environmentID, err = findEnvironmentByName(client, parts[0], parts[1])
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return environmentID, name, target, nil
+}
+
+// findEnvironmentByName returns the ID of the environment with the given name in a project given by its name or ID
+func findEnvironmentByName(client *apiclient.Client, project string, name string) (string, error) {
+	projectID, err := resolveProjectImportID(client, project)
+	if err != nil {
+		return "", err
+	}
+
+	environment, err := client.FindProjectEnvironment(projectID, name)
+	if apiclient.IsNotFoundError(err) {
+		return "", fmt.Errorf("project %q not found", project)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up environment %q: %w", name, err)
+	}
+	if environment == nil {
+		return "", fmt.Errorf("project %q has no environment named %q", project, name)
+	}
+
+	return environment.ID, nil
+}
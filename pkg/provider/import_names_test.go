@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestResolveEnvironmentImportID(t *testing.T) {
+	tests := map[string]struct {
+		importID    string
+		expectID    string
+		expectError bool
+	}{
+		"environment ID":             {importID: "combined", expectID: "combined"},
+		"project and environment":    {importID: "XMC/staging", expectID: "combined"},
+		"project ID and environment": {importID: "project/authoring", expectID: "cm"},
+		"missing environment":        {importID: "XMC/production", expectError: true},
+		"missing project":            {importID: "typo/staging", expectError: true},
+		"too many parts":             {importID: "XMC/staging/extra", expectError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, err := resolveEnvironmentImportID(fixtureClient(t, nil), test.importID)
+
+			if test.expectError {
+				if err == nil {
+					t.Errorf("Expected an error, got ID '%s'", id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if id != test.expectID {
+				t.Errorf("Expected ID '%s', got '%s'", test.expectID, id)
+			}
+		})
+	}
+}
+
+func TestResolveVariableImportID(t *testing.T) {
+	environmentID, name, target, err := resolveVariableImportID(fixtureClient(t, nil), "XMC/staging/API_KEY@EH")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if environmentID != "combined" || name != "API_KEY" || target != "EH" {
+		t.Errorf("Expected combined, API_KEY and EH, got '%s', '%s' and '%s'", environmentID, name, target)
+	}
+
+	_, name, target, err = resolveVariableImportID(fixtureClient(t, nil), "XMC/staging/API_KEY")
+	if err != nil || name != "API_KEY" || target != "" {
+		t.Errorf("Expected API_KEY without target, got '%s' and '%s' (%v)", name, target, err)
+	}
+
+	if _, _, _, err := resolveVariableImportID(fixtureClient(t, nil), "XMC/staging/API_KEY@"); err == nil {
+		t.Error("Expected an error for an empty target")
+	}
+}
+
+func TestEnvironmentResourceImportStateByName(t *testing.T) {
+	r := &environmentResource{client: fixtureClient(t, nil)}
+
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.ImportStateRequest{ID: "XMC/staging"}
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+		},
+	}
+
+	r.ImportState(context.Background(), req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var id types.String
+	resp.State.GetAttribute(context.Background(), path.Root("id"), &id)
+	if id.ValueString() != "combined" {
+		t.Errorf("Expected the resolved environment ID in the state, got '%s'", id.ValueString())
+	}
+}
@@ -0,0 +1,110 @@
+// Shared implementation of the list resources used by terraform query
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// listedResource is a remote object found by a list resource
+type listedResource struct {
+	// displayName is shown to the practitioner in the query results
+	displayName string
+	// identity is the identity model of the managed resource
+	identity any
+	// attributes are the state attributes known from the listing, as set by an import
+	attributes map[string]attr.Value
+}
+
+// listResourceClient returns the client from the provider data of a list resource
+func listResourceClient(req resource.ConfigureRequest) *apiclient.Client {
+	if req.ProviderData == nil {
+		return nil
+	}
+
+	return req.ProviderData.(*apiclient.Client)
+}
+
+// listClientMissing streams an error when the list resource has no client, which happens
+// when the provider was not configured. It returns whether the client is missing
+func listClientMissing(client *apiclient.Client, stream *list.ListResultsStream) bool {
+	if client != nil {
+		return false
+	}
+
+	listErrorResults(stream, "Unconfigured Sitecore API client", "The provider has not been configured, so resources cannot be listed. Please report this issue to the provider developers.")
+	return true
+}
+
+// streamListResults streams the listed objects, up to the requested limit. When the full
+// resource is requested, it is read by the managed resource as after an import, so it
+// matches what a refresh would store
+func streamListResults(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream, r resource.Resource, listed []listedResource) {
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, item := range listed {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = item.displayName
+			result.Diagnostics.Append(result.Identity.Set(ctx, item.identity)...)
+			if req.IncludeResource && !result.Diagnostics.HasError() {
+				readListedResource(ctx, r, item, &result)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+// streamListWarnings streams the warnings about objects that were not listed before the results
+func streamListWarnings(stream *list.ListResultsStream, warnings diag.Diagnostics) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	results := stream.Results
+	stream.Results = func(push func(list.ListResult) bool) {
+		if push(list.ListResult{Diagnostics: warnings}) {
+			results(push)
+		}
+	}
+}
+
+// readListedResource reads the full state of a listed object into the result
+func readListedResource(ctx context.Context, r resource.Resource, item listedResource, result *list.ListResult) {
+	state := tfsdk.State{
+		Schema: result.Resource.Schema,
+		Raw:    result.Resource.Raw,
+	}
+	for name, value := range item.attributes {
+		result.Diagnostics.Append(state.SetAttribute(ctx, path.Root(name), value)...)
+	}
+	if result.Diagnostics.HasError() {
+		return
+	}
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	result.Diagnostics.Append(resp.Diagnostics...)
+
+	result.Resource.Raw = resp.State.Raw
+}
+
+// listErrorResults streams a single error, for failures that prevent listing anything
+func listErrorResults(stream *list.ListResultsStream, summary string, detail string) {
+	var diags diag.Diagnostics
+	diags.AddError(summary, detail)
+
+	stream.Results = list.ListResultsStreamDiagnostics(diags)
+}
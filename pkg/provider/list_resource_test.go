@@ -0,0 +1,356 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// listResults lists the resources of a list resource with the given configuration values
+func listResults(t *testing.T, lr list.ListResource, r resource.ResourceWithIdentity, values map[string]tftypes.Value, includeResource bool) []list.ListResult {
+	schemaResp := list.ListResourceSchemaResponse{}
+	lr.ListResourceConfigSchema(context.Background(), list.ListResourceSchemaRequest{}, &schemaResp)
+
+	configType := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range configType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+
+	identitySchemaResp := resource.IdentitySchemaResponse{}
+	r.IdentitySchema(context.Background(), resource.IdentitySchemaRequest{}, &identitySchemaResp)
+
+	req := list.ListRequest{
+		Config:                 tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(configType, attributes)},
+		IncludeResource:        includeResource,
+		ResourceSchema:         *sourceSchema(r),
+		ResourceIdentitySchema: identitySchemaResp.IdentitySchema,
+	}
+	stream := &list.ListResultsStream{}
+
+	lr.List(context.Background(), req, stream)
+
+	var results []list.ListResult
+	for result := range stream.Results {
+		if result.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", result.Diagnostics)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func TestListResourceSchemas(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("Expected no diagnostics, got %v", resp.Diagnostics[0])
+	}
+
+	for typeName := range resp.ResourceSchemas {
+		if _, ok := resp.ListResourceSchemas[typeName]; !ok {
+			t.Errorf("Expected a list resource for %s", typeName)
+		}
+	}
+}
+
+func TestProjectListResourceList(t *testing.T) {
+	client := fixtureClient(t, nil)
+
+	results := listResults(t, &projectListResource{client: client}, &projectResource{}, nil, true)
+
+	if len(results) != 1 || results[0].DisplayName != "XMC" {
+		t.Fatalf("Expected project XMC, got %+v", results)
+	}
+
+	var identity projectIdentityModel
+	results[0].Diagnostics.Append(results[0].Identity.Get(context.Background(), &identity)...)
+	if identity.ProjectID.ValueString() != "project" {
+		t.Errorf("Expected project ID 'project', got '%s'", identity.ProjectID.ValueString())
+	}
+
+	var state projectResourceModel
+	results[0].Diagnostics.Append(results[0].Resource.Get(context.Background(), &state)...)
+	if results[0].Diagnostics.HasError() {
+		t.Fatalf("Expected the resource to be read, got %v", results[0].Diagnostics)
+	}
+	if state.ID.ValueString() != "project" || state.Name.ValueString() != "XMC" {
+		t.Errorf("Expected the project to be read, got %+v", state)
+	}
+}
+
+func TestEnvironmentListResourceList(t *testing.T) {
+	client := fixtureClient(t, nil)
+
+	tests := map[string]struct {
+		listResource    list.ListResource
+		resource        resource.ResourceWithIdentity
+		projectID       tftypes.Value
+		expectedResults []string
+	}{
+		"combined environments": {
+			listResource:    NewEnvironmentListResource(),
+			resource:        &environmentResource{},
+			projectID:       tftypes.NewValue(tftypes.String, nil),
+			expectedResults: []string{"XMC/staging"},
+		},
+		"CM environments of a project": {
+			listResource:    NewCMEnvironmentListResource(),
+			resource:        &cmEnvironmentResource{},
+			projectID:       tftypes.NewValue(tftypes.String, "project"),
+			expectedResults: []string{"XMC/authoring"},
+		},
+		"editing host environments": {
+			listResource: NewEHEnvironmentListResource(),
+			resource:     &ehEnvironmentResource{},
+			projectID:    tftypes.NewValue(tftypes.String, nil),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.listResource.(*environmentListResource).client = client
+
+			results := listResults(t, test.listResource, test.resource, map[string]tftypes.Value{"project_id": test.projectID}, false)
+
+			if len(results) != len(test.expectedResults) {
+				t.Fatalf("Expected %d results, got %d", len(test.expectedResults), len(results))
+			}
+			for i, result := range results {
+				if result.DisplayName != test.expectedResults[i] {
+					t.Errorf("Expected '%s', got '%s'", test.expectedResults[i], result.DisplayName)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvironmentClientListResourceList(t *testing.T) {
+	client := fixtureClient(t, fixtureRoutes{
+		"/api/clients/v1/environment": `{"items": [
+			{"id": "client", "name": "Deployments", "clientId": "oauth-client", "clientType": 1, "projectId": "project", "environmentId": "combined"},
+			{"id": "other", "name": "Other", "clientId": "oauth-other", "clientType": 1, "projectId": "project", "environmentId": "cm"},
+			{"id": "edge", "name": "Edge", "clientId": "oauth-edge", "clientType": 2, "projectId": "project", "environmentId": "combined"}
+		]}`,
+	})
+
+	lr := NewCMClientListResource().(*environmentClientListResource)
+	lr.client = client
+
+	results := listResults(t, lr, &cmClientResource{}, map[string]tftypes.Value{
+		"environment_id": tftypes.NewValue(tftypes.String, "combined"),
+	}, false)
+
+	if len(results) != 1 || results[0].DisplayName != "Deployments" {
+		t.Fatalf("Expected the CM client of the environment, got %+v", results)
+	}
+
+	var identity environmentClientIdentityModel
+	results[0].Diagnostics.Append(results[0].Identity.Get(context.Background(), &identity)...)
+	if identity.ClientID.ValueString() != "oauth-client" || identity.EnvironmentID.ValueString() != "combined" {
+		t.Errorf("Expected the identity of the CM client, got %+v", identity)
+	}
+}
+
+func TestEnvironmentVariableListResourceList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[
+			{"name": "API_KEY", "value": "editing host", "secret": false, "target": "EH"},
+			{"name": "API_KEY", "value": "secret", "secret": true, "target": "CM"},
+			{"name": "SHARED", "value": "shared", "secret": false}
+		]`)
+	}))
+	defer server.Close()
+
+	client := &apiclient.Client{BaseURL: server.URL, HTTPClient: server.Client(), Token: "test-token"}
+	environmentID := tftypes.NewValue(tftypes.String, "environment")
+
+	t.Run("all targets", func(t *testing.T) {
+		lr := NewEnvironmentVariableListResource().(*environmentVariableListResource)
+		lr.client = client
+
+		results := listResults(t, lr, &environmentVariableResource{}, map[string]tftypes.Value{"environment_id": environmentID}, false)
+
+		if len(results) != 3 || results[0].DisplayName != "API_KEY@EH" || results[2].DisplayName != "SHARED" {
+			t.Fatalf("Expected all variables, got %+v", results)
+		}
+	})
+
+	t.Run("target filter", func(t *testing.T) {
+		lr := NewEnvironmentVariableListResource().(*environmentVariableListResource)
+		lr.client = client
+
+		results := listResults(t, lr, &environmentVariableResource{}, map[string]tftypes.Value{
+			"environment_id": environmentID,
+			"target":         tftypes.NewValue(tftypes.String, "CM"),
+		}, true)
+
+		if len(results) != 1 {
+			t.Fatalf("Expected the CM variable, got %+v", results)
+		}
+
+		var state environmentVariableResourceModel
+		results[0].Diagnostics.Append(results[0].Resource.Get(context.Background(), &state)...)
+		if state.ID.ValueString() != "environment:CM:API_KEY" || state.SecretValue.ValueString() != "secret" {
+			t.Errorf("Expected the CM variable to be read, got %+v", state)
+		}
+	})
+
+	t.Run("CM variables", func(t *testing.T) {
+		lr := NewCMEnvironmentVariableListResource().(*environmentVariableListResource)
+		lr.client = client
+
+		results := listResults(t, lr, &cmEnvironmentVariableResource{}, map[string]tftypes.Value{"environment_id": environmentID}, false)
+
+		if len(results) != 1 {
+			t.Fatalf("Expected the CM variable, got %+v", results)
+		}
+
+		var identity variableIdentityModel
+		results[0].Diagnostics.Append(results[0].Identity.Get(context.Background(), &identity)...)
+		if identity.Name.ValueString() != "API_KEY" {
+			t.Errorf("Expected the identity of the CM variable, got %+v", identity)
+		}
+	})
+}
+
+func TestListResourcesWithUnknownProviderConfig(t *testing.T) {
+	p := &sitecoreProvider{version: "test"}
+	configureResp := configureProvider(t, map[string]tftypes.Value{
+		"client_id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}, false)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", configureResp.Diagnostics)
+	}
+
+	for _, newListResource := range p.ListResources(context.Background()) {
+		lr := newListResource()
+		metadataResp := resource.MetadataResponse{}
+		lr.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "sitecoreai"}, &metadataResp)
+
+		for name, providerData := range map[string]any{"unknown": configureResp.ListResourceData, "unconfigured": nil} {
+			t.Run(metadataResp.TypeName+"/"+name, func(t *testing.T) {
+				lr := newListResource()
+				lr.(list.ListResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{ProviderData: providerData}, &resource.ConfigureResponse{})
+
+				schemaResp := list.ListResourceSchemaResponse{}
+				lr.ListResourceConfigSchema(context.Background(), list.ListResourceSchemaRequest{}, &schemaResp)
+				configType := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+				attributes := map[string]tftypes.Value{}
+				for name, attributeType := range configType.AttributeTypes {
+					attributes[name] = tftypes.NewValue(attributeType, nil)
+				}
+
+				stream := &list.ListResultsStream{}
+				lr.List(context.Background(), list.ListRequest{
+					Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(configType, attributes)},
+				}, stream)
+
+				var details []string
+				for result := range stream.Results {
+					for _, d := range result.Diagnostics.Errors() {
+						details = append(details, d.Detail())
+					}
+				}
+
+				expected := "not known until apply"
+				if providerData == nil {
+					expected = "provider has not been configured"
+				}
+				if len(details) != 1 || !strings.Contains(details[0], expected) {
+					t.Errorf("Expected one error containing %q, got %v", expected, details)
+				}
+			})
+		}
+	}
+}
+
+func TestEnvironmentClientListResourceListSkipsUnresolvedClients(t *testing.T) {
+	var resolved []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/clients/v1/environment":
+			// Older clients only carry the project and environment names
+			_, _ = fmt.Fprint(w, `{"items": [
+				{"id": "gone", "name": "Gone", "clientId": "oauth-gone", "clientType": 1, "projectName": "Deleted", "environmentName": "staging"},
+				{"id": "other", "name": "Other", "clientId": "oauth-other", "clientType": 1, "projectId": "other-project", "environmentId": "cm"},
+				{"id": "first", "name": "First", "clientId": "oauth-first", "clientType": 1, "projectId": "project", "environmentId": "combined"},
+				{"id": "second", "name": "Second", "clientId": "oauth-second", "clientType": 1, "projectName": "XMC", "environmentName": "authoring"}
+			]}`)
+		case "/api/projects/v1":
+			resolved = append(resolved, r.URL.Path)
+			_, _ = fmt.Fprint(w, `[{"id": "project", "name": "XMC"}]`)
+		default:
+			resolved = append(resolved, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	lr := NewCMClientListResource().(*environmentClientListResource)
+	lr.client = &apiclient.Client{BaseURL: server.URL, HTTPClient: server.Client(), Token: "test-token"}
+
+	schemaResp := list.ListResourceSchemaResponse{}
+	lr.ListResourceConfigSchema(context.Background(), list.ListResourceSchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+
+	identitySchemaResp := resource.IdentitySchemaResponse{}
+	(&cmClientResource{}).IdentitySchema(context.Background(), resource.IdentitySchemaRequest{}, &identitySchemaResp)
+
+	req := list.ListRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(configType, map[string]tftypes.Value{
+			"project_id":     tftypes.NewValue(tftypes.String, "project"),
+			"environment_id": tftypes.NewValue(tftypes.String, nil),
+		})},
+		ResourceSchema:         *sourceSchema(&cmClientResource{}),
+		ResourceIdentitySchema: identitySchemaResp.IdentitySchema,
+		Limit:                  1,
+	}
+	stream := &list.ListResultsStream{}
+
+	lr.List(context.Background(), req, stream)
+
+	var names []string
+	var warnings int
+	for result := range stream.Results {
+		if result.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", result.Diagnostics)
+		}
+		warnings += result.Diagnostics.WarningsCount()
+		if result.DisplayName != "" {
+			names = append(names, result.DisplayName)
+		}
+	}
+
+	if fmt.Sprint(names) != "[First]" {
+		t.Errorf("Expected only the first client of the project, got %v", names)
+	}
+	if warnings != 1 {
+		t.Errorf("Expected a warning for the client that could not be resolved, got %d", warnings)
+	}
+
+	// The client of the other project is filtered out and the last client is past the limit,
+	// so only the client without IDs before the limit is resolved
+	if fmt.Sprint(resolved) != "[/api/projects/v1]" {
+		t.Errorf("Expected only the first client without IDs to be resolved, got requests %v", resolved)
+	}
+}
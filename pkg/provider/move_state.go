@@ -0,0 +1,101 @@
+// Moving state between resource types with moved blocks
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// isMoveFrom returns whether a state move comes from the given resource type of this provider
+func isMoveFrom(req resource.MoveStateRequest, typeName string) bool {
+	return req.SourceTypeName == typeName && strings.HasSuffix(req.SourceProviderAddress, "/sitecoreai")
+}
+
+// sourceSchema returns the schema of the resource state is moved from
+func sourceSchema(r resource.Resource) *schema.Schema {
+	resp := resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+
+	return &resp.Schema
+}
+
+// environmentStateMover moves the state of a combined environment to a CM or editing host
+// environment resource. The environment ID is kept, attributes the target resource does not
+// have are dropped and the ones only the target has are filled in by the next refresh
+func environmentStateMover() resource.StateMover {
+	return resource.StateMover{
+		SourceSchema: sourceSchema(NewEnvironmentResource()),
+		StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+			if !isMoveFrom(req, "sitecoreai_environment") || req.SourceState == nil {
+				return
+			}
+
+			var source map[string]tftypes.Value
+			if err := req.SourceState.Raw.As(&source); err != nil {
+				resp.Diagnostics.AddError(
+					"Error moving environment state",
+					"Could not read the state of the combined environment: "+err.Error(),
+				)
+				return
+			}
+
+			targetType, ok := resp.TargetState.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			if !ok {
+				resp.Diagnostics.AddError(
+					"Error moving environment state",
+					"Expected the target resource schema to be an object.",
+				)
+				return
+			}
+
+			attributes := map[string]tftypes.Value{}
+			for name, attributeType := range targetType.AttributeTypes {
+				attributes[name] = tftypes.NewValue(attributeType, nil)
+				if value, ok := source[name]; ok && value.Type().Equal(attributeType) {
+					attributes[name] = value
+				}
+			}
+
+			resp.TargetState.Raw = tftypes.NewValue(targetType, attributes)
+		},
+	}
+}
+
+// environmentVariableStateMover moves the state of an environment variable with the given
+// target to the CM or editing host environment variable resource, which have no target
+func environmentVariableStateMover(target string, targetTypeName string) resource.StateMover {
+	return resource.StateMover{
+		SourceSchema: sourceSchema(NewEnvironmentVariableResource()),
+		StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+			if !isMoveFrom(req, "sitecoreai_environment_variable") || req.SourceState == nil {
+				return
+			}
+
+			var source environmentVariableResourceModel
+			resp.Diagnostics.Append(req.SourceState.Get(ctx, &source)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			if source.Target.ValueString() != target {
+				resp.Diagnostics.AddError(
+					"Error moving environment variable state",
+					fmt.Sprintf("Only environment variables with target %q can be moved to %s, %q has target %q.", target, targetTypeName, source.Name.ValueString(), source.Target.ValueString()),
+				)
+				return
+			}
+
+			// Generate composite ID: environment_id:name
+			moved := source.baseEnvironmentVariableResourceModel
+			moved.ID = types.StringValue(fmt.Sprintf("%s:%s", moved.EnvironmentID.ValueString(), moved.Name.ValueString()))
+
+			resp.Diagnostics.Append(resp.TargetState.Set(ctx, moved)...)
+		},
+	}
+}
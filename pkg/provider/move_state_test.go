@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// moveState calls the state movers of the target resource with the state of the source resource
+func moveState(t *testing.T, target resource.ResourceWithMoveState, sourceTypeName string, source tfsdk.Config) *resource.MoveStateResponse {
+	targetSchema := sourceSchema(target)
+	resp := &resource.MoveStateResponse{
+		TargetState: tfsdk.State{
+			Schema: *targetSchema,
+			Raw:    tftypes.NewValue(targetSchema.Type().TerraformType(context.Background()), nil),
+		},
+	}
+
+	req := resource.MoveStateRequest{
+		SourceProviderAddress: "registry.terraform.io/sitecoreops-terraform/sitecoreai",
+		SourceTypeName:        sourceTypeName,
+		SourceState:           &tfsdk.State{Schema: source.Schema, Raw: source.Raw},
+	}
+
+	for _, mover := range target.MoveState(context.Background()) {
+		mover.StateMover(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() || !resp.TargetState.Raw.IsNull() {
+			break
+		}
+	}
+
+	return resp
+}
+
+func TestCMEnvironmentResourceMoveState(t *testing.T) {
+	source := resourceConfig(t, &environmentResource{}, map[string]tftypes.Value{
+		"id":                tftypes.NewValue(tftypes.String, "test-environment-id"),
+		"name":              tftypes.NewValue(tftypes.String, "authoring"),
+		"project_id":        tftypes.NewValue(tftypes.String, "project"),
+		"repository_branch": tftypes.NewValue(tftypes.String, "main"),
+		"sitecore_version":  tftypes.NewValue(tftypes.String, "1.2"),
+	})
+
+	resp := moveState(t, &cmEnvironmentResource{}, "sitecoreai_environment", source)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var moved cmEnvironmentResourceModel
+	resp.Diagnostics.Append(resp.TargetState.Get(context.Background(), &moved)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected the moved state to match the CM environment schema, got %v", resp.Diagnostics)
+	}
+	if moved.ID.ValueString() != "test-environment-id" || moved.Name.ValueString() != "authoring" || moved.RepositoryBranch.ValueString() != "main" {
+		t.Errorf("Expected the attributes of the combined environment, got %+v", moved)
+	}
+	if !moved.ForceDestroy.IsNull() {
+		t.Error("Expected attributes the combined environment does not have to be null")
+	}
+}
+
+func TestEHEnvironmentResourceMoveStateIgnoresOtherResources(t *testing.T) {
+	source := resourceConfig(t, &cmEnvironmentResource{}, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "test-environment-id"),
+	})
+
+	resp := moveState(t, &ehEnvironmentResource{}, "sitecoreai_cm_environment", source)
+
+	if resp.Diagnostics.HasError() || !resp.TargetState.Raw.IsNull() {
+		t.Errorf("Expected the move to be skipped, got %v", resp.Diagnostics)
+	}
+}
+
+func TestCMEnvironmentVariableResourceMoveState(t *testing.T) {
+	tests := map[string]struct {
+		target      string
+		expectError bool
+	}{
+		"CM variable": {target: "CM"},
+		"EH variable": {target: "EH", expectError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := resourceConfig(t, &environmentVariableResource{}, map[string]tftypes.Value{
+				"id":             tftypes.NewValue(tftypes.String, "environment:"+test.target+":API_KEY"),
+				"environment_id": tftypes.NewValue(tftypes.String, "environment"),
+				"name":           tftypes.NewValue(tftypes.String, "API_KEY"),
+				"target":         tftypes.NewValue(tftypes.String, test.target),
+				"value":          tftypes.NewValue(tftypes.String, "value"),
+			})
+
+			resp := moveState(t, &cmEnvironmentVariableResource{}, "sitecoreai_environment_variable", source)
+
+			if test.expectError {
+				if !resp.Diagnostics.HasError() {
+					t.Error("Expected an error for a variable with another target")
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+			}
+
+			var moved cmEnvironmentVariableResourceModel
+			resp.Diagnostics.Append(resp.TargetState.Get(context.Background(), &moved)...)
+			if moved.ID.ValueString() != "environment:API_KEY" || moved.Value.ValueString() != "value" {
+				t.Errorf("Expected the CM variable ID and value, got %+v", moved)
+			}
+		})
+	}
+}
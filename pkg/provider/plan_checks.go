@@ -0,0 +1,247 @@
+// Plan time checks of the projects and environments resources refer to
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// plannedString returns the planned and current value of a string attribute, and whether
+// the value changes. Values of new resources always change
+func plannedString(ctx context.Context, req resource.ModifyPlanRequest, name string, diagnostics *diag.Diagnostics) (planned types.String, current types.String, changed bool) {
+	diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(name), &planned)...)
+	if req.State.Raw.IsNull() {
+		return planned, types.StringNull(), true
+	}
+
+	diagnostics.Append(req.State.GetAttribute(ctx, path.Root(name), &current)...)
+	return planned, current, !planned.Equal(current)
+}
+
+// environmentTypeName returns the name of an environment type as used in messages
+func environmentTypeName(environment *apiclient.Environment) string {
+	switch environment.Type {
+	case "cm":
+		return "CM"
+	case "eh":
+		return "editing host"
+	}
+
+	return "combined"
+}
+
+// validateProjectExists checks that a project exists. It returns false only when the project is known to be missing
+func validateProjectExists(client *apiclient.Client, projectID types.String, diagnostics *diag.Diagnostics) bool {
+	_, err := client.GetProject(projectID.ValueString())
+	if apiclient.IsNotFoundError(err) {
+		diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Project not found",
+			fmt.Sprintf("No project with ID %q exists in the organization.", projectID.ValueString()),
+		)
+		return false
+	}
+	if err != nil {
+		diagnostics.AddAttributeWarning(
+			path.Root("project_id"),
+			"Could not validate project",
+			"The project could not be read, it is checked when the plan is applied: "+errorDetail(err),
+		)
+	}
+
+	return true
+}
+
+// validateProjectPlan checks that the planned name of a project is not used by another
+// project, unless a new project takes over the existing one
+func validateProjectPlan(ctx context.Context, client *apiclient.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	name, _, changed := plannedString(ctx, req, "name", &resp.Diagnostics)
+	id, _, _ := plannedString(ctx, req, "id", &resp.Diagnostics)
+	adopting := plannedAdoption(ctx, req, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || client == nil || !changed || name.IsUnknown() || adopting {
+		return
+	}
+
+	projects, err := client.GetProjects()
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("name"),
+			"Could not validate project name",
+			"The projects could not be read, the name is checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	for _, project := range projects {
+		if project.Name == name.ValueString() && project.ID != id.ValueString() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Project name already in use",
+				fmt.Sprintf("The organization already has a project named %q (%s). Project names must be unique.", project.Name, project.ID),
+			)
+			return
+		}
+	}
+}
+
+// validateEnvironmentPlan checks that the project of an environment exists and that no other
+// environment in the project has the planned name, unless a new environment takes over the existing one
+func validateEnvironmentPlan(ctx context.Context, client *apiclient.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	projectID, _, projectChanged := plannedString(ctx, req, "project_id", &resp.Diagnostics)
+	name, _, nameChanged := plannedString(ctx, req, "name", &resp.Diagnostics)
+	id, _, _ := plannedString(ctx, req, "id", &resp.Diagnostics)
+	adopting := plannedAdoption(ctx, req, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || client == nil || projectID.IsUnknown() || (!projectChanged && !nameChanged) {
+		return
+	}
+
+	if projectChanged && !validateProjectExists(client, projectID, &resp.Diagnostics) {
+		return
+	}
+	if name.IsUnknown() || adopting {
+		return
+	}
+
+	environments, err := client.GetProjectEnvironments(projectID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("name"),
+			"Could not validate environment name",
+			"The environments of the project could not be read, the name is checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	for _, environment := range environments {
+		if !environment.IsDeleted && environment.Name == name.ValueString() && environment.ID != id.ValueString() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Environment name already in use",
+				fmt.Sprintf("The project already has a %s environment named %q (%s). Environment names must be unique within a project.", environmentTypeName(&environment), environment.Name, environment.ID),
+			)
+			return
+		}
+	}
+}
+
+// validateCMEnvironmentLink checks that an editing host environment is linked to a CM environment in the same project
+func validateCMEnvironmentLink(ctx context.Context, client *apiclient.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	cmEnvironmentID, _, cmChanged := plannedString(ctx, req, "cm_environment_id", &resp.Diagnostics)
+	projectID, _, projectChanged := plannedString(ctx, req, "project_id", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || client == nil || cmEnvironmentID.IsNull() || cmEnvironmentID.IsUnknown() || (!cmChanged && !projectChanged) {
+		return
+	}
+
+	environment, err := client.GetEnvironment(ctx, cmEnvironmentID.ValueString())
+	if apiclient.IsNotFoundError(err) || (err == nil && environment.IsDeleted) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cm_environment_id"),
+			"CM environment not found",
+			fmt.Sprintf("No environment with ID %q exists.", cmEnvironmentID.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("cm_environment_id"),
+			"Could not validate CM environment",
+			"The CM environment could not be read, it is checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	if environment.Type != "cm" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cm_environment_id"),
+			"Not a CM environment",
+			fmt.Sprintf("Environment %q (%s) is a %s environment, editing hosts can only be linked to CM environments.", environment.Name, environment.ID, environmentTypeName(environment)),
+		)
+		return
+	}
+
+	if !projectID.IsUnknown() && environment.ProjectID != projectID.ValueString() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cm_environment_id"),
+			"CM environment in another project",
+			fmt.Sprintf("CM environment %q (%s) belongs to project %s, editing hosts can only be linked to CM environments in the same project.", environment.Name, environment.ID, environment.ProjectID),
+		)
+	}
+}
+
+// validateClientEnvironment checks that the project of a client exists and that its environment belongs to the project
+func validateClientEnvironment(ctx context.Context, client *apiclient.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	projectID, _, projectChanged := plannedString(ctx, req, "project_id", &resp.Diagnostics)
+	environmentID, _, environmentChanged := plannedString(ctx, req, "environment_id", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || client == nil || (!projectChanged && !environmentChanged) {
+		return
+	}
+
+	if !projectID.IsUnknown() && projectChanged && !validateProjectExists(client, projectID, &resp.Diagnostics) {
+		return
+	}
+	if environmentID.IsUnknown() {
+		return
+	}
+
+	environment, err := client.GetEnvironment(ctx, environmentID.ValueString())
+	if apiclient.IsNotFoundError(err) || (err == nil && environment.IsDeleted) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("environment_id"),
+			"Environment not found",
+			fmt.Sprintf("No environment with ID %q exists.", environmentID.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("environment_id"),
+			"Could not validate environment",
+			"The environment could not be read, it is checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !projectID.IsUnknown() && environment.ProjectID != projectID.ValueString() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("environment_id"),
+			"Environment in another project",
+			fmt.Sprintf("Environment %q (%s) belongs to project %s, not to project %s.", environment.Name, environment.ID, environment.ProjectID, projectID.ValueString()),
+		)
+	}
+}
+
+// refuseDestroyDependents refuses to destroy a resource without force_destroy that still has
+// dependents, so the apply does not fail partway through the teardown
+func refuseDestroyDependents(ctx context.Context, client *apiclient.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, summary string, reason string, getDependents func(id string) (*apiclient.Dependents, error)) {
+	var id types.String
+	var forceDestroy types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("force_destroy"), &forceDestroy)...)
+	if resp.Diagnostics.HasError() || client == nil || forceDestroy.ValueBool() {
+		return
+	}
+
+	dependents, err := getDependents(id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Could not check dependents",
+			"The dependents could not be read, they are checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	if len(dependents.Environments) > 0 {
+		resp.Diagnostics.AddError(
+			summary,
+			"It cannot be destroyed because "+reason+": "+strings.Join(dependents.EnvironmentNames(), ", ")+". "+
+				"Delete them first, or set force_destroy = true and apply before destroying to delete them with it.",
+		)
+	}
+}
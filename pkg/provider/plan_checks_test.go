@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// createPlanRequest builds the plan request of a new resource with the given attribute values
+func createPlanRequest(t *testing.T, r resource.Resource, values map[string]tftypes.Value) resource.ModifyPlanRequest {
+	config := resourceConfig(t, r, values)
+
+	return resource.ModifyPlanRequest{
+		Config: config,
+		Plan:   tfsdk.Plan{Schema: config.Schema, Raw: config.Raw},
+		State:  tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(config.Raw.Type(), nil)},
+	}
+}
+
+func TestValidateEHEnvironmentPlan(t *testing.T) {
+	tests := map[string]struct {
+		projectID       string
+		name            string
+		cmEnvironmentID string
+		adoptExisting   bool
+		expectError     string
+	}{
+		"valid": {
+			projectID:       "project",
+			name:            "editing",
+			cmEnvironmentID: "cm",
+		},
+		"missing project": {
+			projectID:       "typo",
+			name:            "editing",
+			cmEnvironmentID: "cm",
+			expectError:     "Project not found",
+		},
+		"duplicate name": {
+			projectID:       "project",
+			name:            "staging",
+			cmEnvironmentID: "cm",
+			expectError:     "Environment name already in use",
+		},
+		"adopting the environment with the same name": {
+			projectID:       "project",
+			name:            "staging",
+			cmEnvironmentID: "cm",
+			adoptExisting:   true,
+		},
+		"linked to a combined environment": {
+			projectID:       "project",
+			name:            "editing",
+			cmEnvironmentID: "combined",
+			expectError:     "Not a CM environment",
+		},
+		"linked to a missing environment": {
+			projectID:       "project",
+			name:            "editing",
+			cmEnvironmentID: "missing",
+			expectError:     "CM environment not found",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &ehEnvironmentResource{client: fixtureClient(t, nil)}
+			req := createPlanRequest(t, r, map[string]tftypes.Value{
+				"project_id":        tftypes.NewValue(tftypes.String, test.projectID),
+				"name":              tftypes.NewValue(tftypes.String, test.name),
+				"cm_environment_id": tftypes.NewValue(tftypes.String, test.cmEnvironmentID),
+				"adopt_existing":    tftypes.NewValue(tftypes.Bool, test.adoptExisting),
+			})
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+
+			r.ModifyPlan(context.Background(), req, &resp)
+
+			if test.expectError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Expected no errors, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != test.expectError {
+				t.Errorf("Expected error '%s', got %v", test.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestValidateClientEnvironment(t *testing.T) {
+	tests := map[string]struct {
+		projectID     string
+		environmentID string
+		expectError   string
+	}{
+		"valid": {
+			projectID:     "project",
+			environmentID: "cm",
+		},
+		"missing environment": {
+			projectID:     "project",
+			environmentID: "missing",
+			expectError:   "Environment not found",
+		},
+		"missing project": {
+			projectID:     "typo",
+			environmentID: "cm",
+			expectError:   "Project not found",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &cmClientResource{client: fixtureClient(t, nil)}
+			req := createPlanRequest(t, r, map[string]tftypes.Value{
+				"project_id":     tftypes.NewValue(tftypes.String, test.projectID),
+				"environment_id": tftypes.NewValue(tftypes.String, test.environmentID),
+			})
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+
+			r.ModifyPlan(context.Background(), req, &resp)
+
+			if test.expectError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Expected no errors, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != test.expectError {
+				t.Errorf("Expected error '%s', got %v", test.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestRefuseDestroyDependents(t *testing.T) {
+	client := fixtureClient(t, fixtureRoutes{
+		"/api/projects/v2/project/environments": `[{"id": "cm", "name": "authoring", "projectId": "project", "type": "cm"}, {"id": "eh", "name": "editing", "projectId": "project", "type": "eh", "editingHostEnvironmentDetails": {"cmEnvironmentId": "cm"}}]`,
+	})
+
+	tests := map[string]struct {
+		resource     resource.ResourceWithModifyPlan
+		id           string
+		forceDestroy bool
+		expected     string
+	}{
+		"project":                           {resource: &projectResource{client: client}, id: "project", expected: `"authoring" (cm)`},
+		"project with force_destroy":        {resource: &projectResource{client: client}, id: "project", forceDestroy: true},
+		"CM environment":                    {resource: &cmEnvironmentResource{client: client}, id: "cm", expected: `"editing" (eh)`},
+		"CM environment with force_destroy": {resource: &cmEnvironmentResource{client: client}, id: "cm", forceDestroy: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := resourceConfig(t, test.resource, map[string]tftypes.Value{
+				"id":            tftypes.NewValue(tftypes.String, test.id),
+				"force_destroy": tftypes.NewValue(tftypes.Bool, test.forceDestroy),
+			})
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: state.Schema, Raw: tftypes.NewValue(state.Raw.Type(), nil)},
+				Plan:   tfsdk.Plan{Schema: state.Schema, Raw: tftypes.NewValue(state.Raw.Type(), nil)},
+				State:  tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+
+			test.resource.ModifyPlan(context.Background(), req, resp)
+
+			if test.expected == "" {
+				if len(resp.Diagnostics) > 0 {
+					t.Errorf("Expected no diagnostics, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), test.expected) {
+				t.Errorf("Expected an error naming %s, got %v", test.expected, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -79,7 +79,7 @@ func (d *projectDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading projects",
-			"Could not read projects: "+err.Error(),
+			"Could not read projects: "+errorDetail(err),
 		)
 		return
 	}
@@ -0,0 +1,72 @@
+// Project list resource implementation
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ list.ListResource              = &projectListResource{}
+	_ list.ListResourceWithConfigure = &projectListResource{}
+)
+
+// NewProjectListResource is a helper function to simplify the provider implementation
+func NewProjectListResource() list.ListResource {
+	return &projectListResource{}
+}
+
+// projectListResource is the list resource implementation
+type projectListResource struct {
+	client *apiclient.Client
+}
+
+// Metadata returns the resource type name
+func (r *projectListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+// ListResourceConfigSchema defines the schema for the list resource
+func (r *projectListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the projects of the organization",
+	}
+}
+
+// Configure adds the provider configured client to the list resource
+func (r *projectListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	r.client = listResourceClient(req)
+}
+
+// List streams the projects of the organization
+func (r *projectListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	if listClientMissing(r.client, stream) {
+		return
+	}
+
+	projects, err := r.client.GetProjects()
+	if err != nil {
+		listErrorResults(stream, "Error listing projects", "Could not read projects: "+errorDetail(err))
+		return
+	}
+
+	listed := make([]listedResource, 0, len(projects))
+	for _, project := range projects {
+		listed = append(listed, listedResource{
+			displayName: project.Name,
+			identity:    projectIdentityModel{ProjectID: types.StringValue(project.ID)},
+			attributes: map[string]attr.Value{
+				"id": types.StringValue(project.ID),
+			},
+		})
+	}
+
+	streamListResults(ctx, req, stream, &projectResource{client: r.client}, listed)
+}
@@ -3,7 +3,9 @@ package provider
 
 import (
 	"context"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,9 +17,12 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &projectResource{}
-	_ resource.ResourceWithConfigure   = &projectResource{}
-	_ resource.ResourceWithImportState = &projectResource{}
+	_ resource.Resource                 = &projectResource{}
+	_ resource.ResourceWithConfigure    = &projectResource{}
+	_ resource.ResourceWithIdentity     = &projectResource{}
+	_ resource.ResourceWithImportState  = &projectResource{}
+	_ resource.ResourceWithModifyPlan   = &projectResource{}
+	_ resource.ResourceWithUpgradeState = &projectResource{}
 )
 
 // NewProjectResource is a helper function to simplify the provider implementation
@@ -32,8 +37,12 @@ type projectResource struct {
 
 // projectResourceModel maps the resource schema data
 type projectResourceModel struct {
-	ID   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	ID            types.String   `tfsdk:"id"`
+	Name          types.String   `tfsdk:"name"`
+	Region        types.String   `tfsdk:"region"`
+	ForceDestroy  types.Bool     `tfsdk:"force_destroy"`
+	AdoptExisting types.Bool     `tfsdk:"adopt_existing"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name
@@ -42,8 +51,9 @@ func (r *projectResource) Metadata(_ context.Context, req resource.MetadataReque
 }
 
 // Schema defines the schema for the resource
-func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *projectResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     schemaVersion,
 		Description: "Environments ¤ Manages a Sitecore project",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -57,10 +67,59 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Description: "The name of the project",
 				Required:    true,
 			},
+			"region": schema.StringAttribute{
+				Description: "The region the project is hosted in, see the sitecoreai_regions data source. Defaults to the region of the organization, changing it forces a new project",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"force_destroy": schema.BoolAttribute{
+				Description: "Whether to also delete the environments of the project, and their clients, when it is destroyed. " +
+					"Without it, the plan refuses to destroy a project that still has environments. Defaults to false",
+				Optional: true,
+			},
+			"adopt_existing": adoptExistingAttribute("project", "in the organization"),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// IdentitySchema defines the identity of the resource
+func (r *projectResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = projectIdentitySchema()
+}
+
+// ModifyPlan checks that a new region is available to the organization and that
+// the name is not used by another project, or refuses to destroy
+// the project while it has environments
+func (r *projectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		refuseDestroyDependents(ctx, r.client, req, resp, "Project has environments", "the project has environments", func(id string) (*apiclient.Dependents, error) {
+			return r.client.GetProjectDependents(id)
+		})
+		return
+	}
+
+	var plannedRegion, currentRegion types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("region"), &plannedRegion)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("region"), &currentRegion)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateRegion(r.client, plannedRegion, currentRegion, &resp.Diagnostics)
+	validateProjectPlan(ctx, r.client, req, resp)
+}
+
 // Configure adds the provider configured client to the resource
 func (r *projectResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -80,23 +139,45 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	// Create the project
-	project := apiclient.Project{
-		Name: plan.Name.ValueString(),
+	// Take over an existing project with the same name when requested
+	var createdProject *apiclient.Project
+	var err error
+	if plan.AdoptExisting.ValueBool() {
+		createdProject, err = adoptProject(r.client, plan.Name.ValueString(), plan.Region)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error adopting project",
+				"Could not take over the existing project: "+errorDetail(err),
+			)
+			return
+		}
 	}
 
-	createdProject, err := r.client.CreateProject(project)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating project",
-			"Could not create project, unexpected error: "+err.Error(),
-		)
-		return
+	if createdProject == nil {
+		// Create the project
+		project := apiclient.Project{
+			Name: plan.Name.ValueString(),
+		}
+		if !plan.Region.IsNull() && !plan.Region.IsUnknown() {
+			project.Zone = plan.Region.ValueString()
+		}
+
+		createdProject, err = r.client.CreateProject(project)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating project",
+				"Could not create project, unexpected error: "+errorDetail(err),
+			)
+			return
+		}
 	}
 
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(createdProject.ID)
 	plan.Name = types.StringValue(createdProject.Name)
+	if createdProject.Zone != "" || plan.Region.IsUnknown() {
+		plan.Region = types.StringValue(createdProject.Zone)
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -104,6 +185,8 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, projectIdentityModel{ProjectID: plan.ID}, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data
@@ -118,16 +201,23 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	// Get project from API
 	project, err := r.client.GetProject(state.ID.ValueString())
+	if apiclient.IsNotFoundError(err) {
+		removeDeletedResource(ctx, resp, "Project", state.ID.ValueString())
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading project",
-			"Could not read project ID "+state.ID.ValueString()+": "+err.Error(),
+			"Could not read project ID "+state.ID.ValueString()+": "+errorDetail(err),
 		)
 		return
 	}
 
 	// Overwrite items with refreshed state
 	state.Name = types.StringValue(project.Name)
+	if project.Zone != "" {
+		state.Region = types.StringValue(project.Zone)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -135,6 +225,8 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, projectIdentityModel{ProjectID: state.ID}, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
@@ -147,33 +239,35 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	// Update the project
-	project := apiclient.Project{
-		ID:   plan.ID.ValueString(),
-		Name: plan.Name.ValueString(),
+	// Get current state
+	var state projectResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	err := r.client.UpdateProject(plan.ID.ValueString(), project)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating project",
-			"Could not update project, unexpected error: "+err.Error(),
-		)
-		return
+	// Only send the attributes the plan changes, the others keep their current values.
+	// Nothing is sent when the plan only changes attributes Terraform keeps to itself, such as timeouts
+	changes := apiclient.UpdateProjectRequest{}
+	if !plan.Name.Equal(state.Name) {
+		changes.Name = plan.Name.ValueStringPointer()
 	}
 
-	// Fetch updated project from API
-	updatedProject, err := r.client.GetProject(plan.ID.ValueString())
+	updatedProject, err := r.client.PatchProject(plan.ID.ValueString(), changes)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error reading updated project",
-			"Could not read updated project ID "+plan.ID.ValueString()+": "+err.Error(),
+			"Error updating project",
+			"Could not update project, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
 
 	// Update state with refreshed values
 	plan.Name = types.StringValue(updatedProject.Name)
+	if updatedProject.Zone != "" {
+		plan.Region = types.StringValue(updatedProject.Zone)
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -181,6 +275,8 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	setIdentity(ctx, resp.Identity, projectIdentityModel{ProjectID: plan.ID}, &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success
@@ -193,12 +289,52 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, r.client.Timeouts.Delete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// The plan already refuses to destroy a project with environments, this
+	// catches environments that were created since the plan was made
+	dependents, err := r.client.GetProjectDependents(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting project",
+			"Could not read the environments of the project: "+errorDetail(err),
+		)
+		return
+	}
+
+	if len(dependents.Environments) > 0 && !state.ForceDestroy.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Project has environments",
+			"The project cannot be deleted while it has environments: "+strings.Join(dependents.EnvironmentNames(), ", ")+". "+
+				"Delete them first, or set force_destroy = true and apply before destroying the project to delete them with it.",
+		)
+		return
+	}
+
+	if state.ForceDestroy.ValueBool() {
+		err = r.client.DeleteDependents(ctx, dependents)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error deleting project",
+				"Could not delete the environments and clients of the project: "+errorDetail(err),
+			)
+			return
+		}
+	}
+
 	// Delete the project
-	err := r.client.DeleteProject(state.ID.ValueString())
+	err = r.client.DeleteProject(state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting project",
-			"Could not delete project, unexpected error: "+err.Error(),
+			"Could not delete project, unexpected error: "+errorDetail(err),
 		)
 		return
 	}
@@ -206,6 +342,32 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 // ImportState imports an existing project into Terraform state
 func (r *projectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Take the project ID from the identity when imported by identity
+	var identity projectIdentityModel
+	if importIdentity(ctx, req, &identity, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ProjectID)...)
+		}
+		return
+	}
+
+	// Resolve the import ID and save it to the id attribute
+	// Expected format: project_id or project name
+	id, err := resolveProjectImportID(r.client, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing project",
+			"Could not resolve import ID: "+errorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// UpgradeState upgrades state written with earlier schema versions
+func (r *projectResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: unversionedStateUpgrader(nil),
+	}
 }
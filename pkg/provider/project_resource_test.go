@@ -2,9 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 func TestProjectResourceMetadata(t *testing.T) {
@@ -43,6 +49,10 @@ func TestProjectResourceSchema(t *testing.T) {
 		t.Error("Expected schema to have name attribute")
 	}
 
+	if _, ok := resp.Schema.Attributes["force_destroy"]; !ok {
+		t.Error("Expected schema to have force_destroy attribute")
+	}
+
 	// Check attribute properties - simplified for now
 	// Note: A more comprehensive test would check the exact attribute properties
 	// but this requires more complex attribute type checking
@@ -70,3 +80,66 @@ func TestProjectResourceConfigure(t *testing.T) {
 //  	resp := resource.ImportStateResponse{}
 //  	r.ImportState(context.Background(), req, &resp)
 // }
+
+func TestProjectResourceDeleteWithEnvironments(t *testing.T) {
+	tests := map[string]struct {
+		forceDestroy bool
+		expectError  bool
+		expected     []string
+	}{
+		"without force_destroy": {
+			forceDestroy: false,
+			expectError:  true,
+			expected:     nil,
+		},
+		"with force_destroy": {
+			forceDestroy: true,
+			expectError:  false,
+			expected:     []string{"/api/environments/v1/test-environment-id", "/api/projects/v1/test-project-id"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "DELETE":
+					deleted = append(deleted, r.URL.Path)
+				case r.URL.Path == "/api/projects/v2/test-project-id/environments":
+					_, _ = fmt.Fprint(w, `[{"id": "test-environment-id", "name": "staging"}]`)
+				case r.URL.Path == "/api/clients/v1/environment":
+					_, _ = fmt.Fprint(w, `{"items": []}`)
+				default:
+					// The environment is gone once deleted
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			r := &projectResource{client: &apiclient.Client{
+				BaseURL:    server.URL,
+				HTTPClient: server.Client(),
+				Token:      "test-token",
+				Timeouts:   apiclient.DefaultTimeouts,
+			}}
+
+			config := resourceConfig(t, r, map[string]tftypes.Value{
+				"id":            tftypes.NewValue(tftypes.String, "test-project-id"),
+				"name":          tftypes.NewValue(tftypes.String, "XMC"),
+				"force_destroy": tftypes.NewValue(tftypes.Bool, test.forceDestroy),
+			})
+			state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+			resp := resource.DeleteResponse{State: state}
+
+			r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() != test.expectError {
+				t.Errorf("Expected error: %v, got diagnostics: %v", test.expectError, resp.Diagnostics)
+			}
+			if fmt.Sprint(deleted) != fmt.Sprint(test.expected) {
+				t.Errorf("Expected deletions %v, got %v", test.expected, deleted)
+			}
+		})
+	}
+}
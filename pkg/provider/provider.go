@@ -5,19 +5,27 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ provider.Provider = &sitecoreProvider{}
+	_ provider.Provider                  = &sitecoreProvider{}
+	_ provider.ProviderWithListResources = &sitecoreProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation
@@ -39,9 +47,19 @@ type sitecoreProvider struct {
 
 // sitecoreProviderModel maps provider schema data to a Go type
 type sitecoreProviderModel struct {
-	ClientID     types.String `tfsdk:"client_id"`
-	ClientSecret types.String `tfsdk:"client_secret"`
-	UseCLI       types.Bool   `tfsdk:"use_cli"`
+	ClientID        types.String `tfsdk:"client_id"`
+	ClientSecret    types.String `tfsdk:"client_secret"`
+	UseCLI          types.Bool   `tfsdk:"use_cli"`
+	TokenCache      types.Bool   `tfsdk:"token_cache"`
+	DefaultTimeouts types.Object `tfsdk:"default_timeouts"`
+}
+
+// providerTimeoutsModel maps the default_timeouts attribute
+type providerTimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
 }
 
 // Metadata returns the provider type name
@@ -68,6 +86,33 @@ func (p *sitecoreProvider) Schema(_ context.Context, _ provider.SchemaRequest, r
 				Description: "Use Sitecore CLI authentication (searches for .sitecore/user.json)",
 				Optional:    true,
 			},
+			"token_cache": schema.BoolAttribute{
+				Description: "Cache client credentials tokens on disk and reuse them across runs until shortly before they expire. Can also be enabled with SITECOREAI_TOKEN_CACHE, and the location set with SITECOREAI_TOKEN_CACHE_DIR",
+				Optional:    true,
+			},
+			"default_timeouts": schema.SingleNestedAttribute{
+				Description: "Default timeouts for long-running operations, such as waiting for environments to be provisioned. " +
+					"Can be overridden with a timeouts block on each resource",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"create": schema.StringAttribute{
+						Description: "A duration such as \"30s\" or \"2h45m\" for creating resources, defaults to 30m",
+						Optional:    true,
+					},
+					"read": schema.StringAttribute{
+						Description: "A duration such as \"30s\" or \"2h45m\" for reading resources, defaults to 5m",
+						Optional:    true,
+					},
+					"update": schema.StringAttribute{
+						Description: "A duration such as \"30s\" or \"2h45m\" for updating resources, defaults to 30m",
+						Optional:    true,
+					},
+					"delete": schema.StringAttribute{
+						Description: "A duration such as \"30s\" or \"2h45m\" for deleting resources, defaults to 30m",
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -82,14 +127,50 @@ func (p *sitecoreProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	// If practitioner provided a configuration value that is not known until
+	// apply, eg. credentials from a sitecoreai_deploy_client created in the
+	// same plan, the resources using the provider are deferred
+	if config.ClientID.IsUnknown() || config.ClientSecret.IsUnknown() || config.UseCLI.IsUnknown() || config.TokenCache.IsUnknown() || config.DefaultTimeouts.IsUnknown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{
+				Reason: provider.DeferredReasonProviderConfigUnknown,
+			}
+			return
+		}
+
+		// Without deferral support new resources can still be planned, but
+		// anything that needs the API will fail until the values are known
+		resp.Diagnostics.AddWarning(
+			"Unknown Sitecore API Configuration",
+			"The provider configuration depends on values that are not known until apply. "+
+				"Resources and data sources that need to call the Sitecore API cannot be read until then. "+
+				"Use a Terraform version supporting deferred actions, or apply the resources providing the credentials first.",
+		)
+		client := apiclient.NewUnconfiguredClient("the provider configuration is not known until apply")
+		resp.DataSourceData = client
+		resp.ResourceData = client
+		resp.ListResourceData = client
+		return
+	}
+
 	var client *apiclient.Client
 	var err error
 
 	// Handle environment variables
-	// Check if CLI authentication is requested
-	useCLI := os.Getenv("SITECOREAI_USE_CLI") == "1" || os.Getenv("SITECOREAI_USE_CLI") == "true"
+	// Check if CLI authentication is requested, and remember why so the
+	// diagnostics can explain which credentials were used
+	useCLI := false
+	useCLIReason := ""
+	if os.Getenv("SITECOREAI_USE_CLI") == "1" || os.Getenv("SITECOREAI_USE_CLI") == "true" {
+		useCLI = true
+		useCLIReason = "the SITECOREAI_USE_CLI environment variable is set"
+	}
 	if !config.UseCLI.IsNull() {
 		useCLI = config.UseCLI.ValueBool()
+		useCLIReason = ""
+		if useCLI {
+			useCLIReason = "use_cli = true is set in the provider configuration"
+		}
 	}
 
 	// Use traditional client_id/client_secret authentication
@@ -104,27 +185,46 @@ func (p *sitecoreProvider) Configure(ctx context.Context, req provider.Configure
 		clientSecret = config.ClientSecret.ValueString()
 	}
 
-	// If practitioner provided a configuration value for any of the
-	// attributes, it must be a known value
-	if config.ClientID.IsUnknown() || config.ClientSecret.IsUnknown() || config.UseCLI.IsUnknown() {
-		resp.Diagnostics.AddError(
-			"Unknown Sitecore API Configuration",
-			"Cannot use unknown values for Sitecore API configuration",
-		)
-		return
-	}
-
 	if useCLI {
 		// Try CLI authentication
 		client, err = apiclient.NewClientFromCLI("")
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Sitecore CLI Authentication Failed",
-				"Unable to authenticate using Sitecore CLI: "+err.Error(),
+				fmt.Sprintf("Sitecore CLI authentication was selected because %s, but the CLI configuration could not be used: %s", useCLIReason, err.Error()),
+			)
+			return
+		}
+		client.CredentialSource = apiclient.CredentialSourceCLI
+
+		// The provider does not refresh CLI tokens, so an expired token will always fail
+		if expiresAt, ok := client.CLITokenExpiry(); ok && time.Now().After(expiresAt) {
+			resp.Diagnostics.AddError(
+				"Sitecore CLI Token Expired",
+				fmt.Sprintf("Sitecore CLI authentication was selected because %s, but the access token in %s expired at %s. "+
+					"Run 'dotnet sitecore cloud login' to refresh it.", useCLIReason, client.CliConfigPath, expiresAt.Format(time.RFC3339)),
 			)
 			return
 		}
 	} else {
+		// Explain exactly which part of the client credentials is missing
+		var missing []string
+		if clientID == "" {
+			missing = append(missing, "client_id is not set in the provider configuration or SITECOREAI_CLIENT_ID")
+		}
+		if clientSecret == "" {
+			missing = append(missing, "client_secret is not set in the provider configuration or SITECOREAI_CLIENT_SECRET")
+		}
+		if len(missing) > 0 {
+			resp.Diagnostics.AddError(
+				"Missing Sitecore API Credentials",
+				"Client credentials authentication was selected because Sitecore CLI authentication is not enabled, but "+
+					strings.Join(missing, ", and ")+". "+
+					"Set the client credentials, or set use_cli = true or SITECOREAI_USE_CLI=1 to use Sitecore CLI authentication.",
+			)
+			return
+		}
+
 		// Create a new Sitecore API client
 		client, err = apiclient.NewClient(clientID, clientSecret)
 		if err != nil {
@@ -134,22 +234,86 @@ func (p *sitecoreProvider) Configure(ctx context.Context, req provider.Configure
 			)
 			return
 		}
+
+		client.CredentialSource = apiclient.CredentialSourceEnv
+		if !config.ClientID.IsNull() && len(config.ClientID.ValueString()) > 0 {
+			client.CredentialSource = apiclient.CredentialSourceConfig
+		}
 	}
 
-	// Authenticate the client
-	err = client.Authenticate()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Sitecore API Client Authentication Failed",
-			"Unable to authenticate Sitecore API client: "+err.Error(),
-		)
+	// Check if the token cache is requested
+	useTokenCache := os.Getenv("SITECOREAI_TOKEN_CACHE") == "1" || os.Getenv("SITECOREAI_TOKEN_CACHE") == "true"
+	if !config.TokenCache.IsNull() {
+		useTokenCache = config.TokenCache.ValueBool()
+	}
+
+	if useTokenCache {
+		client.TokenCache, err = apiclient.NewTokenCache("")
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Sitecore API Token Cache Unavailable",
+				"Unable to set up the token cache: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Override the default timeouts of long-running operations
+	client.Timeouts = providerTimeouts(ctx, config.DefaultTimeouts, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Authentication is deferred until the first API call, so planning
+	// does not need network access when nothing has to be read.
 	// Make the Sitecore API client available during data source and resource
 	// type Configure methods
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.ListResourceData = client
+}
+
+// providerTimeouts returns the default timeouts with the values from the provider configuration applied
+func providerTimeouts(ctx context.Context, value types.Object, diagnostics *diag.Diagnostics) apiclient.Timeouts {
+	result := apiclient.DefaultTimeouts
+	if value.IsNull() || value.IsUnknown() {
+		return result
+	}
+
+	var config providerTimeoutsModel
+	diagnostics.Append(value.As(ctx, &config, basetypes.ObjectAsOptions{})...)
+	if diagnostics.HasError() {
+		return result
+	}
+
+	durations := map[string]struct {
+		value  types.String
+		target *time.Duration
+	}{
+		"create": {config.Create, &result.Create},
+		"read":   {config.Read, &result.Read},
+		"update": {config.Update, &result.Update},
+		"delete": {config.Delete, &result.Delete},
+	}
+
+	for name, duration := range durations {
+		if duration.value.IsNull() || duration.value.IsUnknown() {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(duration.value.ValueString())
+		if err != nil || parsed <= 0 {
+			diagnostics.AddAttributeError(
+				path.Root("default_timeouts").AtName(name),
+				"Invalid Default Timeout",
+				fmt.Sprintf("The %s timeout must be a positive duration such as \"30s\" or \"2h45m\", got %q", name, duration.value.ValueString()),
+			)
+			continue
+		}
+		*duration.target = parsed
+	}
+
+	return result
 }
 
 // DataSources defines the data sources implemented in the provider
@@ -158,6 +322,10 @@ func (p *sitecoreProvider) DataSources(_ context.Context) []func() datasource.Da
 		NewProjectDataSource,
 		NewEnvironmentDataSource,
 		NewEditingSecretDataSource,
+		NewCallerIdentityDataSource,
+		NewRegionsDataSource,
+		NewSitecoreVersionsDataSource,
+		NewDeletedEnvironmentsDataSource,
 	}
 }
 
@@ -177,3 +345,20 @@ func (p *sitecoreProvider) Resources(_ context.Context) []func() resource.Resour
 		NewEHEnvironmentVariableResource,
 	}
 }
+
+// ListResources defines the list resources used by terraform query, one for each resource type
+func (p *sitecoreProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewProjectListResource,
+		NewEnvironmentListResource,
+		NewCMEnvironmentListResource,
+		NewEHEnvironmentListResource,
+		NewCMClientListResource,
+		NewEdgeClientListResource,
+		NewDeployClientListResource,
+		NewEditingHostBuildClientListResource,
+		NewEnvironmentVariableListResource,
+		NewCMEnvironmentVariableListResource,
+		NewEHEnvironmentVariableListResource,
+	}
+}
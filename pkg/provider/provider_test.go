@@ -2,9 +2,14 @@ package provider
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
 )
 
 func TestProviderMetadata(t *testing.T) {
@@ -56,6 +61,218 @@ func TestProviderConfigure(t *testing.T) {
 			t.Error("Expected provider to be properly initialized")
 		}
 	})
+
+	t.Run("does not authenticate during configure", func(t *testing.T) {
+		t.Setenv("SITECOREAI_CLIENT_ID", "test-client-id")
+		t.Setenv("SITECOREAI_CLIENT_SECRET", "test-client-secret")
+		t.Setenv("SITECOREAI_USE_CLI", "")
+		t.Setenv("SITECOREAI_TOKEN_CACHE", "")
+
+		resp := configureProvider(t, map[string]tftypes.Value{}, false)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+		}
+
+		client, ok := resp.ResourceData.(*apiclient.Client)
+		if !ok {
+			t.Fatal("Expected resource data to be an API client")
+		}
+		if client.Token != "" {
+			t.Error("Expected client not to be authenticated yet")
+		}
+	})
+
+	t.Run("explains missing client credentials", func(t *testing.T) {
+		t.Setenv("SITECOREAI_CLIENT_ID", "test-client-id")
+		t.Setenv("SITECOREAI_CLIENT_SECRET", "")
+		t.Setenv("SITECOREAI_USE_CLI", "")
+
+		resp := configureProvider(t, map[string]tftypes.Value{}, false)
+
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("Expected an error when client_secret is missing")
+		}
+
+		detail := resp.Diagnostics.Errors()[0].Detail()
+		if !strings.Contains(detail, "SITECOREAI_CLIENT_SECRET") || strings.Contains(detail, "SITECOREAI_CLIENT_ID") {
+			t.Errorf("Expected error to name only the missing client_secret, got '%s'", detail)
+		}
+	})
+
+	t.Run("explains missing CLI configuration", func(t *testing.T) {
+		t.Setenv("SITECOREAI_USE_CLI", "1")
+		t.Chdir(t.TempDir())
+
+		resp := configureProvider(t, map[string]tftypes.Value{}, false)
+
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("Expected an error when user.json cannot be found")
+		}
+
+		detail := resp.Diagnostics.Errors()[0].Detail()
+		if !strings.Contains(detail, "SITECOREAI_USE_CLI") || !strings.Contains(detail, "user.json") {
+			t.Errorf("Expected error to name the credential source and user.json, got '%s'", detail)
+		}
+	})
+
+	t.Run("names use_cli when set in the configuration", func(t *testing.T) {
+		t.Setenv("SITECOREAI_USE_CLI", "1")
+		t.Chdir(t.TempDir())
+
+		resp := configureProvider(t, map[string]tftypes.Value{
+			"use_cli": tftypes.NewValue(tftypes.Bool, true),
+		}, false)
+
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("Expected an error when user.json cannot be found")
+		}
+
+		detail := resp.Diagnostics.Errors()[0].Detail()
+		if !strings.Contains(detail, "use_cli = true") || strings.Contains(detail, "SITECOREAI_USE_CLI") {
+			t.Errorf("Expected error to name use_cli in the provider configuration, got '%s'", detail)
+		}
+	})
+
+	t.Run("use_cli false overrides the environment", func(t *testing.T) {
+		t.Setenv("SITECOREAI_CLIENT_ID", "test-client-id")
+		t.Setenv("SITECOREAI_CLIENT_SECRET", "test-client-secret")
+		t.Setenv("SITECOREAI_USE_CLI", "1")
+		t.Setenv("SITECOREAI_TOKEN_CACHE", "")
+		t.Chdir(t.TempDir())
+
+		resp := configureProvider(t, map[string]tftypes.Value{
+			"use_cli": tftypes.NewValue(tftypes.Bool, false),
+		}, false)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected client credentials to be used, got %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("defers when configuration is unknown", func(t *testing.T) {
+		resp := configureProvider(t, map[string]tftypes.Value{
+			"client_id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}, true)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+		}
+		if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+			t.Errorf("Expected provider configuration to be deferred, got %v", resp.Deferred)
+		}
+	})
+
+	t.Run("warns when configuration is unknown and deferral is not allowed", func(t *testing.T) {
+		resp := configureProvider(t, map[string]tftypes.Value{
+			"client_secret": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}, false)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+		}
+		if resp.Diagnostics.WarningsCount() != 1 {
+			t.Errorf("Expected a warning, got %v", resp.Diagnostics)
+		}
+
+		client, ok := resp.ResourceData.(*apiclient.Client)
+		if !ok {
+			t.Fatal("Expected resource data to be an API client")
+		}
+		if err := client.EnsureTokenValid(); err == nil {
+			t.Error("Expected API calls to fail until the configuration is known")
+		}
+	})
+
+	timeoutsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"create": tftypes.String,
+		"read":   tftypes.String,
+		"update": tftypes.String,
+		"delete": tftypes.String,
+	}}
+
+	t.Run("overrides default timeouts", func(t *testing.T) {
+		t.Setenv("SITECOREAI_CLIENT_ID", "test-client-id")
+		t.Setenv("SITECOREAI_CLIENT_SECRET", "test-client-secret")
+		t.Setenv("SITECOREAI_USE_CLI", "")
+
+		resp := configureProvider(t, map[string]tftypes.Value{
+			"default_timeouts": tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, "1h"),
+				"read":   tftypes.NewValue(tftypes.String, nil),
+				"update": tftypes.NewValue(tftypes.String, nil),
+				"delete": tftypes.NewValue(tftypes.String, "45m"),
+			}),
+		}, false)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+		}
+
+		client := resp.ResourceData.(*apiclient.Client)
+		if client.Timeouts.Create != time.Hour || client.Timeouts.Delete != 45*time.Minute {
+			t.Errorf("Expected configured create and delete timeouts, got %v", client.Timeouts)
+		}
+		if client.Timeouts.Read != apiclient.DefaultTimeouts.Read || client.Timeouts.Update != apiclient.DefaultTimeouts.Update {
+			t.Errorf("Expected default read and update timeouts, got %v", client.Timeouts)
+		}
+	})
+
+	t.Run("rejects invalid default timeouts", func(t *testing.T) {
+		t.Setenv("SITECOREAI_CLIENT_ID", "test-client-id")
+		t.Setenv("SITECOREAI_CLIENT_SECRET", "test-client-secret")
+		t.Setenv("SITECOREAI_USE_CLI", "")
+
+		resp := configureProvider(t, map[string]tftypes.Value{
+			"default_timeouts": tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, "ten minutes"),
+				"read":   tftypes.NewValue(tftypes.String, nil),
+				"update": tftypes.NewValue(tftypes.String, nil),
+				"delete": tftypes.NewValue(tftypes.String, nil),
+			}),
+		}, false)
+
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("Expected an error for an invalid duration")
+		}
+	})
+}
+
+// configureProvider calls Configure with the given attribute values, all other attributes are null
+func configureProvider(t *testing.T, values map[string]tftypes.Value, deferralAllowed bool) *provider.ConfigureResponse {
+	ctx := context.Background()
+	p := sitecoreProvider{version: "test"}
+
+	schemaResp := provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("Expected provider schema to be an object")
+	}
+
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attributes),
+		},
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{
+			DeferralAllowed: deferralAllowed,
+		},
+	}
+	resp := provider.ConfigureResponse{}
+
+	p.Configure(ctx, req, &resp)
+
+	return &resp
 }
 
 func TestProviderDataSources(t *testing.T) {
@@ -0,0 +1,178 @@
+// Regions data source implementation
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ datasource.DataSource              = &regionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &regionsDataSource{}
+)
+
+// NewRegionsDataSource is a helper function to simplify the provider implementation
+func NewRegionsDataSource() datasource.DataSource {
+	return &regionsDataSource{}
+}
+
+// regionsDataSource is the data source implementation
+type regionsDataSource struct {
+	client *apiclient.Client
+}
+
+// regionsDataSourceModel maps the data source schema data
+type regionsDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Names   types.List   `tfsdk:"names"`
+	Regions types.List   `tfsdk:"regions"`
+}
+
+// regionModelAttributeTypes are the attribute types of an element of the regions list
+var regionModelAttributeTypes = map[string]attr.Type{
+	"name":         types.StringType,
+	"display_name": types.StringType,
+	"is_default":   types.BoolType,
+}
+
+// Metadata returns the data source type name
+func (d *regionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_regions"
+}
+
+// Schema defines the schema for the data source
+func (d *regionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Environments ¤ Use this data source to list the regions environments can be created in",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source",
+				Computed:    true,
+			},
+			"names": schema.ListAttribute{
+				Description: "The names of the available regions, as used by the region attribute of projects and environments",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"regions": schema.ListNestedAttribute{
+				Description: "The available regions",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the region",
+							Computed:    true,
+						},
+						"display_name": schema.StringAttribute{
+							Description: "The display name of the region",
+							Computed:    true,
+						},
+						"is_default": schema.BoolAttribute{
+							Description: "Whether new environments are created in this region by default",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source
+func (d *regionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*apiclient.Client)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (d *regionsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Get the regions available to the organization
+	regions, err := d.client.GetRegions()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading regions",
+			"Could not read regions: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Map the regions to the schema
+	names := make([]string, 0, len(regions))
+	elements := make([]attr.Value, 0, len(regions))
+	for _, region := range regions {
+		names = append(names, region.Name)
+
+		element, diags := types.ObjectValue(regionModelAttributeTypes, map[string]attr.Value{
+			"name":         types.StringValue(region.Name),
+			"display_name": types.StringValue(region.DisplayName),
+			"is_default":   types.BoolValue(region.IsDefault),
+		})
+		resp.Diagnostics.Append(diags...)
+		elements = append(elements, element)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state regionsDataSourceModel
+	var diags diag.Diagnostics
+	state.ID = types.StringValue("regions")
+	state.Names, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	state.Regions, diags = types.ListValue(types.ObjectType{AttrTypes: regionModelAttributeTypes}, elements)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// validateRegion checks at plan time that a region is available to the organization.
+// The check is skipped when the region is not set or not known yet, or did not change
+func validateRegion(client *apiclient.Client, planned types.String, current types.String, diagnostics *diag.Diagnostics) {
+	if client == nil || planned.IsNull() || planned.IsUnknown() || planned.Equal(current) {
+		return
+	}
+
+	regions, err := client.GetRegions()
+	if err != nil {
+		diagnostics.AddAttributeWarning(
+			path.Root("region"),
+			"Could not validate region",
+			"The available regions could not be read, the region is checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	names := make([]string, 0, len(regions))
+	for _, region := range regions {
+		if region.Name == planned.ValueString() {
+			return
+		}
+		names = append(names, region.Name)
+	}
+
+	diagnostics.AddAttributeError(
+		path.Root("region"),
+		"Invalid region",
+		fmt.Sprintf("The region %q is not available to the organization. Available regions are: %s", planned.ValueString(), strings.Join(names, ", ")),
+	)
+}
@@ -0,0 +1,144 @@
+// Resource identities of projects, environments, clients and variables
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// projectIdentityModel maps the identity schema of projects
+type projectIdentityModel struct {
+	ProjectID types.String `tfsdk:"project_id"`
+}
+
+// environmentIdentityModel maps the identity schema of environments
+type environmentIdentityModel struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	EnvironmentID types.String `tfsdk:"environment_id"`
+}
+
+// organizationClientIdentityModel maps the identity schema of organization clients
+type organizationClientIdentityModel struct {
+	ClientID types.String `tfsdk:"client_id"`
+}
+
+// environmentClientIdentityModel maps the identity schema of environment clients
+type environmentClientIdentityModel struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	EnvironmentID types.String `tfsdk:"environment_id"`
+	ClientID      types.String `tfsdk:"client_id"`
+}
+
+// variableIdentityModel maps the identity schema of CM and editing host environment variables
+type variableIdentityModel struct {
+	EnvironmentID types.String `tfsdk:"environment_id"`
+	Name          types.String `tfsdk:"name"`
+}
+
+// targetedVariableIdentityModel maps the identity schema of environment variables with a target
+type targetedVariableIdentityModel struct {
+	EnvironmentID types.String `tfsdk:"environment_id"`
+	Name          types.String `tfsdk:"name"`
+	Target        types.String `tfsdk:"target"`
+}
+
+// projectIdentitySchema returns the identity schema of projects
+func projectIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"project_id": identityschema.StringAttribute{
+				Description:       "The ID of the project",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// environmentIdentitySchema returns the identity schema of environments
+func environmentIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"project_id": identityschema.StringAttribute{
+				Description:       "The ID of the project the environment belongs to",
+				OptionalForImport: true,
+			},
+			"environment_id": identityschema.StringAttribute{
+				Description:       "The ID of the environment",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// clientIdentitySchema returns the identity schema of clients, which for environment
+// clients includes the project and environment the client belongs to
+func clientIdentitySchema(inEnvironment bool) identityschema.Schema {
+	attributes := map[string]identityschema.Attribute{
+		"client_id": identityschema.StringAttribute{
+			Description:       "The OAuth client ID of the client",
+			RequiredForImport: true,
+		},
+	}
+	if inEnvironment {
+		attributes["project_id"] = identityschema.StringAttribute{
+			Description:       "The ID of the project the client belongs to",
+			OptionalForImport: true,
+		}
+		attributes["environment_id"] = identityschema.StringAttribute{
+			Description:       "The ID of the environment the client belongs to",
+			OptionalForImport: true,
+		}
+	}
+
+	return identityschema.Schema{Attributes: attributes}
+}
+
+// variableIdentitySchema returns the identity schema of environment variables, with
+// the target for the environment variable resource that manages any target
+func variableIdentitySchema(withTarget bool) identityschema.Schema {
+	attributes := map[string]identityschema.Attribute{
+		"environment_id": identityschema.StringAttribute{
+			Description:       "The ID of the environment the variable belongs to",
+			RequiredForImport: true,
+		},
+		"name": identityschema.StringAttribute{
+			Description:       "The name of the environment variable",
+			RequiredForImport: true,
+		},
+	}
+	if withTarget {
+		attributes["target"] = identityschema.StringAttribute{
+			Description:       "The target of the environment variable, empty for all targets",
+			OptionalForImport: true,
+		}
+	}
+
+	return identityschema.Schema{Attributes: attributes}
+}
+
+// setIdentity sets the identity of a resource after it was created, read or updated. The
+// identity is nil when the resource is used outside Terraform, as in unit tests
+func setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, model any, diagnostics *diag.Diagnostics) {
+	if identity == nil {
+		return
+	}
+
+	diagnostics.Append(identity.Set(ctx, model)...)
+}
+
+// importIdentity reads the identity given in an import block into the model, and returns
+// whether the resource is imported by identity rather than by import ID
+func importIdentity(ctx context.Context, req resource.ImportStateRequest, model any, diagnostics *diag.Diagnostics) bool {
+	if req.ID != "" || req.Identity == nil {
+		return false
+	}
+
+	diagnostics.Append(req.Identity.Get(ctx, model)...)
+
+	return true
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// resourceIdentity returns the identity of the resource set to the model, or a null identity when the model is nil
+func resourceIdentity(t *testing.T, r resource.ResourceWithIdentity, model any) *tfsdk.ResourceIdentity {
+	schemaResp := resource.IdentitySchemaResponse{}
+	r.IdentitySchema(context.Background(), resource.IdentitySchemaRequest{}, &schemaResp)
+
+	identity := &tfsdk.ResourceIdentity{
+		Schema: schemaResp.IdentitySchema,
+		Raw:    tftypes.NewValue(schemaResp.IdentitySchema.Type().TerraformType(context.Background()), nil),
+	}
+	if model != nil {
+		if diags := identity.Set(context.Background(), model); diags.HasError() {
+			t.Fatalf("Failed to set identity: %v", diags)
+		}
+	}
+
+	return identity
+}
+
+func TestResourceIdentitySchemas(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+
+	resp, err := server.GetResourceIdentitySchemas(context.Background(), &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("Expected no diagnostics, got %v", resp.Diagnostics[0])
+	}
+
+	p := sitecoreProvider{}
+	for _, newResource := range p.Resources(context.Background()) {
+		metadataResp := resource.MetadataResponse{}
+		newResource().Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "sitecoreai"}, &metadataResp)
+
+		if _, ok := resp.IdentitySchemas[metadataResp.TypeName]; !ok {
+			t.Errorf("Expected %s to have an identity schema", metadataResp.TypeName)
+		}
+	}
+}
+
+func TestProjectResourceImportStateByIdentity(t *testing.T) {
+	r := &projectResource{}
+	resp := importStateResponse(r)
+	req := resource.ImportStateRequest{
+		Identity: resourceIdentity(t, r, projectIdentityModel{ProjectID: types.StringValue("project")}),
+	}
+
+	r.ImportState(context.Background(), req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var id types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("id"), &id)...)
+	if id.ValueString() != "project" {
+		t.Errorf("Expected ID 'project', got '%s'", id.ValueString())
+	}
+}
+
+func TestEnvironmentVariableResourceImportStateByIdentity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[
+			{"name": "API_KEY", "value": "editing host", "secret": false, "target": "EH"},
+			{"name": "API_KEY", "value": "secret", "secret": true, "target": "CM"}
+		]`)
+	}))
+	defer server.Close()
+
+	r := &environmentVariableResource{base: baseEnvironmentVariableResource{client: &apiclient.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}}}
+	resp := importStateResponse(r)
+	req := resource.ImportStateRequest{
+		Identity: resourceIdentity(t, r, targetedVariableIdentityModel{
+			EnvironmentID: types.StringValue("environment"),
+			Name:          types.StringValue("API_KEY"),
+			Target:        types.StringValue("EH"),
+		}),
+	}
+
+	r.ImportState(context.Background(), req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var state environmentVariableResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.ID.ValueString() != "environment:EH:API_KEY" {
+		t.Errorf("Expected ID 'environment:EH:API_KEY', got '%s'", state.ID.ValueString())
+	}
+	if state.Value.ValueString() != "editing host" || state.Target.ValueString() != "EH" {
+		t.Errorf("Expected the EH variable, got value '%s' and target '%s'", state.Value.ValueString(), state.Target.ValueString())
+	}
+}
+
+func TestEnvironmentVariableResourceReadSetsIdentity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"name": "API_KEY", "value": "secret", "secret": true, "target": "CM"}]`)
+	}))
+	defer server.Close()
+
+	r := &environmentVariableResource{base: baseEnvironmentVariableResource{client: &apiclient.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+	}}}
+
+	config := resourceConfig(t, r, map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "environment:CM:API_KEY"),
+		"environment_id": tftypes.NewValue(tftypes.String, "environment"),
+		"name":           tftypes.NewValue(tftypes.String, "API_KEY"),
+		"target":         tftypes.NewValue(tftypes.String, "CM"),
+	})
+	state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+	resp := resource.ReadResponse{State: state, Identity: resourceIdentity(t, r, nil)}
+
+	r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var identity targetedVariableIdentityModel
+	resp.Diagnostics.Append(resp.Identity.Get(context.Background(), &identity)...)
+	if identity.EnvironmentID.ValueString() != "environment" || identity.Name.ValueString() != "API_KEY" || identity.Target.ValueString() != "CM" {
+		t.Errorf("Expected the identity of the CM variable, got %+v", identity)
+	}
+}
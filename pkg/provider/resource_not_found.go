@@ -0,0 +1,40 @@
+// Handling of resources that were deleted outside Terraform
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// removeDeletedResource removes a resource that no longer exists from the state, so
+// the next plan proposes to create it again instead of failing to read it
+func removeDeletedResource(ctx context.Context, resp *resource.ReadResponse, description string, id string) {
+	tflog.Warn(ctx, description+" was deleted outside Terraform, removing it from the state", map[string]interface{}{
+		"id": id,
+	})
+
+	resp.State.RemoveResource(ctx)
+}
+
+// modifyRestorePlan plans to restore an environment that was deleted outside Terraform,
+// when restore_if_deleted is set. Read only keeps such environments in the state in that case
+func modifyRestorePlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var isDeleted, restoreIfDeleted types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("is_deleted"), &isDeleted)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("restore_if_deleted"), &restoreIfDeleted)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isDeleted.ValueBool() && restoreIfDeleted.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("is_deleted"), types.BoolValue(false))...)
+	}
+}
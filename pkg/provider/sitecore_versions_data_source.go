@@ -0,0 +1,275 @@
+// Sitecore versions data source implementation
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitecoreops-terraform/terraform-provider-sitecoreai/pkg/apiclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ datasource.DataSource              = &sitecoreVersionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &sitecoreVersionsDataSource{}
+)
+
+// NewSitecoreVersionsDataSource is a helper function to simplify the provider implementation
+func NewSitecoreVersionsDataSource() datasource.DataSource {
+	return &sitecoreVersionsDataSource{}
+}
+
+// sitecoreVersionsDataSource is the data source implementation
+type sitecoreVersionsDataSource struct {
+	client *apiclient.Client
+}
+
+// sitecoreVersionsDataSourceModel maps the data source schema data
+type sitecoreVersionsDataSourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	DefaultVersion types.String `tfsdk:"default_version"`
+	Versions       types.List   `tfsdk:"versions"`
+	VersionDetails types.List   `tfsdk:"version_details"`
+}
+
+// sitecoreVersionModelAttributeTypes are the attribute types of an element of the version_details list
+var sitecoreVersionModelAttributeTypes = map[string]attr.Type{
+	"version":       types.StringType,
+	"major_version": types.Int64Type,
+	"minor_version": types.Int64Type,
+	"display_name":  types.StringType,
+	"is_default":    types.BoolType,
+}
+
+// Metadata returns the data source type name
+func (d *sitecoreVersionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sitecore_versions"
+}
+
+// Schema defines the schema for the data source
+func (d *sitecoreVersionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Environments ¤ Use this data source to list the Sitecore versions environments can be created with or upgraded to",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source",
+				Computed:    true,
+			},
+			"default_version": schema.StringAttribute{
+				Description: "The version new environments are created with by default",
+				Computed:    true,
+			},
+			"versions": schema.ListAttribute{
+				Description: "The supported versions in the major.minor format, as used by the sitecore_version attribute of environments",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"version_details": schema.ListNestedAttribute{
+				Description: "The supported versions",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version": schema.StringAttribute{
+							Description: "The version in the major.minor format",
+							Computed:    true,
+						},
+						"major_version": schema.Int64Attribute{
+							Description: "The major version",
+							Computed:    true,
+						},
+						"minor_version": schema.Int64Attribute{
+							Description: "The minor version",
+							Computed:    true,
+						},
+						"display_name": schema.StringAttribute{
+							Description: "The display name of the version",
+							Computed:    true,
+						},
+						"is_default": schema.BoolAttribute{
+							Description: "Whether new environments are created with this version by default",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source
+func (d *sitecoreVersionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*apiclient.Client)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (d *sitecoreVersionsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Get the supported Sitecore versions
+	versions, err := d.client.GetSitecoreVersions()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Sitecore versions",
+			"Could not read Sitecore versions: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Map the versions to the schema
+	var state sitecoreVersionsDataSourceModel
+	state.ID = types.StringValue("sitecore_versions")
+	state.DefaultVersion = types.StringNull()
+
+	names := make([]string, 0, len(versions))
+	elements := make([]attr.Value, 0, len(versions))
+	for _, version := range versions {
+		names = append(names, version.String())
+		if version.IsDefault {
+			state.DefaultVersion = types.StringValue(version.String())
+		}
+
+		element, diags := types.ObjectValue(sitecoreVersionModelAttributeTypes, map[string]attr.Value{
+			"version":       types.StringValue(version.String()),
+			"major_version": types.Int64Value(int64(version.MajorVersion)),
+			"minor_version": types.Int64Value(int64(version.MinorVersion)),
+			"display_name":  types.StringValue(version.DisplayName),
+			"is_default":    types.BoolValue(version.IsDefault),
+		})
+		resp.Diagnostics.Append(diags...)
+		elements = append(elements, element)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+	state.Versions, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	state.VersionDetails, diags = types.ListValue(types.ObjectType{AttrTypes: sitecoreVersionModelAttributeTypes}, elements)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// plannedSitecoreVersion returns the Sitecore version set in the plan.
+// ok is false when the version is not set, not known yet or invalid
+func plannedSitecoreVersion(value types.String) (version apiclient.SitecoreVersion, ok bool) {
+	if value.IsNull() || value.IsUnknown() {
+		return apiclient.SitecoreVersion{}, false
+	}
+
+	version, err := apiclient.ParseSitecoreVersion(value.ValueString())
+	if err != nil {
+		return apiclient.SitecoreVersion{}, false
+	}
+
+	return version, true
+}
+
+// validateSitecoreVersionConfig checks that sitecore_version is in the major.minor format
+// and agrees with sitecore_major_version when both are set
+func validateSitecoreVersionConfig(version types.String, majorVersion types.Int64, diagnostics *diag.Diagnostics) {
+	if version.IsNull() || version.IsUnknown() {
+		return
+	}
+
+	parsed, err := apiclient.ParseSitecoreVersion(version.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("sitecore_version"),
+			"Invalid Sitecore version",
+			"The Sitecore version must be in the major.minor format, for example \"1.5\": "+errorDetail(err),
+		)
+		return
+	}
+
+	if !majorVersion.IsNull() && !majorVersion.IsUnknown() && majorVersion.ValueInt64() != int64(parsed.MajorVersion) {
+		diagnostics.AddAttributeError(
+			path.Root("sitecore_major_version"),
+			"Conflicting Sitecore versions",
+			fmt.Sprintf("sitecore_major_version is %d but sitecore_version is %s. Remove sitecore_major_version, or set both to the same major version.", majorVersion.ValueInt64(), version.ValueString()),
+		)
+	}
+}
+
+// validateSitecoreVersion checks at plan time that a Sitecore version is supported.
+// The check is skipped when the version is not set or not known yet, or did not change
+func validateSitecoreVersion(client *apiclient.Client, planned types.String, current types.String, diagnostics *diag.Diagnostics) {
+	if client == nil || planned.IsNull() || planned.IsUnknown() || planned.Equal(current) {
+		return
+	}
+
+	versions, err := client.GetSitecoreVersions()
+	if err != nil {
+		diagnostics.AddAttributeWarning(
+			path.Root("sitecore_version"),
+			"Could not validate Sitecore version",
+			"The supported Sitecore versions could not be read, the version is checked when the plan is applied: "+errorDetail(err),
+		)
+		return
+	}
+
+	names := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if version.String() == planned.ValueString() {
+			return
+		}
+		names = append(names, version.String())
+	}
+
+	diagnostics.AddAttributeError(
+		path.Root("sitecore_version"),
+		"Unsupported Sitecore version",
+		fmt.Sprintf("The Sitecore version %q is not supported. Supported versions are: %s", planned.ValueString(), strings.Join(names, ", ")),
+	)
+}
+
+// modifySitecoreVersionPlan validates a changed sitecore_version and marks the version
+// attributes that are not configured as unknown, when the other one changes, as both
+// follow the version the environment runs after an upgrade
+func modifySitecoreVersionPlan(ctx context.Context, client *apiclient.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plannedVersion, currentVersion, configVersion types.String
+	var plannedMajorVersion, currentMajorVersion, configMajorVersion types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("sitecore_version"), &plannedVersion)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("sitecore_major_version"), &plannedMajorVersion)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sitecore_version"), &configVersion)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sitecore_major_version"), &configMajorVersion)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("sitecore_version"), &currentVersion)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("sitecore_major_version"), &currentMajorVersion)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateSitecoreVersion(client, plannedVersion, currentVersion, &resp.Diagnostics)
+
+	// New environments get both values from the API anyway
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if !plannedVersion.IsNull() && !plannedVersion.IsUnknown() && !plannedVersion.Equal(currentVersion) && configMajorVersion.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sitecore_major_version"), types.Int64Unknown())...)
+	}
+	if !plannedMajorVersion.IsNull() && !plannedMajorVersion.IsUnknown() && !plannedMajorVersion.Equal(currentMajorVersion) && configVersion.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sitecore_version"), types.StringUnknown())...)
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestSitecoreVersionsDataSourceMetadata(t *testing.T) {
+	d := sitecoreVersionsDataSource{}
+
+	req := datasource.MetadataRequest{
+		ProviderTypeName: "sitecore",
+	}
+	resp := datasource.MetadataResponse{}
+
+	d.Metadata(context.Background(), req, &resp)
+
+	if resp.TypeName != "sitecore_sitecore_versions" {
+		t.Errorf("Expected TypeName to be 'sitecore_sitecore_versions', got '%s'", resp.TypeName)
+	}
+}
+
+func TestSitecoreVersionsDataSourceSchema(t *testing.T) {
+	d := sitecoreVersionsDataSource{}
+
+	req := datasource.SchemaRequest{}
+	resp := datasource.SchemaResponse{}
+
+	d.Schema(context.Background(), req, &resp)
+
+	// Check that the expected attributes are present
+	for _, name := range []string{"id", "default_version", "versions", "version_details"} {
+		if _, ok := resp.Schema.Attributes[name]; !ok {
+			t.Errorf("Expected schema to have %s attribute", name)
+		}
+	}
+}
+
+func TestSitecoreVersionsDataSourceConfigure(t *testing.T) {
+	d := sitecoreVersionsDataSource{}
+
+	// Test with nil provider data
+	req := datasource.ConfigureRequest{}
+	resp := datasource.ConfigureResponse{}
+
+	d.Configure(context.Background(), req, &resp)
+
+	// Client should remain nil when no provider data is provided
+	if d.client != nil {
+		t.Error("Expected client to remain nil when no provider data is provided")
+	}
+}
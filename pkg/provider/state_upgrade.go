@@ -0,0 +1,107 @@
+// Upgrades of state written with earlier schema versions
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// schemaVersion is the current schema version of all resources. When the shape of the
+// state of a resource changes, bump it and add an upgrader from the previous version
+const schemaVersion = 1
+
+// stateMigration adjusts the attributes of upgraded state that cannot be carried over as is
+type stateMigration func(attributes map[string]tftypes.Value) error
+
+// unversionedStateUpgrader upgrades state written before resources had a schema version.
+// Attributes added since are null until the next refresh, attributes that no longer
+// exist are dropped, and the migration, when set, adjusts the remaining attributes
+func unversionedStateUpgrader(migrate stateMigration) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			objectType, ok := resp.State.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			if !ok {
+				resp.Diagnostics.AddError(
+					"Error upgrading state",
+					"Expected the resource schema to be an object.",
+				)
+				return
+			}
+
+			attributes, err := priorStateAttributes(req, objectType)
+			if err == nil && migrate != nil {
+				err = migrate(attributes)
+			}
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error upgrading state",
+					"Could not upgrade the state written by an earlier version of the provider: "+err.Error(),
+				)
+				return
+			}
+
+			resp.State.Raw = tftypes.NewValue(objectType, attributes)
+		},
+	}
+}
+
+// priorStateAttributes decodes the attributes of the raw prior state that still exist in the schema
+func priorStateAttributes(req resource.UpgradeStateRequest, objectType tftypes.Object) (map[string]tftypes.Value, error) {
+	if req.RawState == nil {
+		return nil, fmt.Errorf("missing prior state")
+	}
+
+	var prior map[string]json.RawMessage
+	if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+		return nil, fmt.Errorf("failed to decode prior state: %v", err)
+	}
+
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+
+		raw, ok := prior[name]
+		if !ok {
+			continue
+		}
+
+		value, err := tftypes.ValueFromJSON(raw, attributeType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode attribute %s: %v", name, err)
+		}
+		attributes[name] = value
+	}
+
+	return attributes, nil
+}
+
+// migrateVariableID rewrites the composite ID of an environment variable, which was stored
+// as environment_id:name or environment_id:target:name depending on how it was created
+func migrateVariableID(withTarget bool) stateMigration {
+	return func(attributes map[string]tftypes.Value) error {
+		var environmentID, name, target string
+		if err := attributes["environment_id"].As(&environmentID); err != nil {
+			return fmt.Errorf("failed to read environment_id: %v", err)
+		}
+		if err := attributes["name"].As(&name); err != nil {
+			return fmt.Errorf("failed to read name: %v", err)
+		}
+
+		id := fmt.Sprintf("%s:%s", environmentID, name)
+		if withTarget {
+			if value, ok := attributes["target"]; ok && !value.IsNull() {
+				if err := value.As(&target); err != nil {
+					return fmt.Errorf("failed to read target: %v", err)
+				}
+			}
+			id = environmentVariableID(environmentID, target, name)
+		}
+
+		attributes["id"] = tftypes.NewValue(tftypes.String, id)
+		return nil
+	}
+}
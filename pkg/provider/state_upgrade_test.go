@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// upgradeState upgrades the given JSON state of schema version 0 to the current schema of the resource
+func upgradeState(t *testing.T, r resource.ResourceWithUpgradeState, priorState string) *resource.UpgradeStateResponse {
+	upgrader, ok := r.UpgradeState(context.Background())[0]
+	if !ok {
+		t.Fatal("Expected an upgrader from schema version 0")
+	}
+
+	resp := &resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: *sourceSchema(r)},
+	}
+	req := resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte(priorState)},
+	}
+
+	upgrader.StateUpgrader(context.Background(), req, resp)
+
+	return resp
+}
+
+func TestResourcesHaveSchemaVersion(t *testing.T) {
+	p := sitecoreProvider{}
+
+	for _, newResource := range p.Resources(context.Background()) {
+		r := newResource()
+
+		metadataResp := resource.MetadataResponse{}
+		r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "sitecoreai"}, &metadataResp)
+
+		if version := sourceSchema(r).Version; version != schemaVersion {
+			t.Errorf("Expected %s to have schema version %d, got %d", metadataResp.TypeName, schemaVersion, version)
+		}
+
+		upgradable, ok := r.(resource.ResourceWithUpgradeState)
+		if !ok {
+			t.Errorf("Expected %s to upgrade state", metadataResp.TypeName)
+			continue
+		}
+		for version := int64(0); version < schemaVersion; version++ {
+			if _, ok := upgradable.UpgradeState(context.Background())[version]; !ok {
+				t.Errorf("Expected %s to upgrade state from schema version %d", metadataResp.TypeName, version)
+			}
+		}
+	}
+}
+
+func TestProjectResourceUpgradeStateFromVersion0(t *testing.T) {
+	// State written before region and force_destroy existed, by a configuration that still set description
+	resp := upgradeState(t, &projectResource{}, `{"id": "project", "name": "XMC", "description": "Corporate website"}`)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var state projectResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected the upgraded state to match the schema, got %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "project" || state.Name.ValueString() != "XMC" {
+		t.Errorf("Expected the ID and name to be kept, got %+v", state)
+	}
+	if !state.Region.IsNull() || !state.ForceDestroy.IsNull() {
+		t.Errorf("Expected attributes added since to be null, got %+v", state)
+	}
+}
+
+func TestEnvironmentResourceUpgradeStateFromVersion0(t *testing.T) {
+	resp := upgradeState(t, &environmentResource{}, `{
+		"id": "environment",
+		"name": "staging",
+		"project_id": "project",
+		"is_prod": false,
+		"sitecore_major_version": 1,
+		"timeouts": {"create": "60m"}
+	}`)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+	}
+
+	var state environmentResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected the upgraded state to match the schema, got %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "environment" || state.SitecoreMajorVersion.ValueInt64() != 1 {
+		t.Errorf("Expected the prior attributes to be kept, got %+v", state)
+	}
+
+	createTimeout, diags := state.Timeouts.Create(context.Background(), 0)
+	if diags.HasError() || createTimeout.String() != "1h0m0s" {
+		t.Errorf("Expected the create timeout to be kept, got %s (%v)", createTimeout, diags)
+	}
+}
+
+func TestEnvironmentVariableResourcesUpgradeStateFromVersion0(t *testing.T) {
+	tests := map[string]struct {
+		resource   resource.ResourceWithUpgradeState
+		priorState string
+		expectedID string
+	}{
+		"variable created with a target": {
+			resource:   &environmentVariableResource{},
+			priorState: `{"id": "environment:CM:API_KEY", "environment_id": "environment", "name": "API_KEY", "target": "CM", "value": "value"}`,
+			expectedID: "environment:CM:API_KEY",
+		},
+		"variable updated with a target": {
+			resource:   &environmentVariableResource{},
+			priorState: `{"id": "environment:API_KEY", "environment_id": "environment", "name": "API_KEY", "target": "CM", "value": "value"}`,
+			expectedID: "environment:CM:API_KEY",
+		},
+		"variable without a target": {
+			resource:   &environmentVariableResource{},
+			priorState: `{"id": "environment:API_KEY", "environment_id": "environment", "name": "API_KEY", "target": null, "value": "value"}`,
+			expectedID: "environment::API_KEY",
+		},
+		"CM variable": {
+			resource:   &cmEnvironmentVariableResource{},
+			priorState: `{"id": "environment:CM:API_KEY", "environment_id": "environment", "name": "API_KEY", "secret_value": "secret"}`,
+			expectedID: "environment:API_KEY",
+		},
+		"EH variable": {
+			resource:   &ehEnvironmentVariableResource{},
+			priorState: `{"id": "environment:API_KEY", "environment_id": "environment", "name": "API_KEY", "value": "value"}`,
+			expectedID: "environment:API_KEY",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := upgradeState(t, test.resource, test.priorState)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Expected no errors, got %v", resp.Diagnostics)
+			}
+
+			var state baseEnvironmentVariableResourceModel
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("id"), &state.ID)...)
+			if state.ID.ValueString() != test.expectedID {
+				t.Errorf("Expected ID '%s', got '%s'", test.expectedID, state.ID.ValueString())
+			}
+		})
+	}
+}